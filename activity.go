@@ -0,0 +1,70 @@
+package todo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	ActionListCreated    = "list_created"
+	ActionListUpdated    = "list_updated"
+	ActionListDeleted    = "list_deleted"
+	ActionListRestored   = "list_restored"
+	ActionListShared     = "list_shared"
+	ActionListUnshared   = "list_unshared"
+	ActionItemCreated    = "item_created"
+	ActionItemUpdated    = "item_updated"
+	ActionItemDone       = "item_completed"
+	ActionItemDeleted    = "item_deleted"
+	ActionItemRestored   = "item_restored"
+	ActionItemMoved      = "item_moved"
+	ActionItemsReordered = "items_reordered"
+)
+
+// Activity is an entry of the history of a list. The actor is unset once the
+// user is deleted.
+type Activity struct {
+	Id        int       `json:"id" db:"id"`
+	ListId    int       `json:"list_id" db:"list_id"`
+	ItemId    *int      `json:"item_id,omitempty" db:"item_id"`
+	UserId    *int      `json:"actor_id" db:"user_id"`
+	Username  *string   `json:"actor" db:"username"`
+	Action    string    `json:"action" db:"action"`
+	Changes   Changes   `json:"changes" db:"changes"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Changes maps the changed fields of a list or item to their old and new
+// values, or the usernames of collaborators to their old and new roles. It is
+// stored as jsonb.
+type Changes map[string]Change
+
+// Value returns a string, as lib/pq would send []byte as bytea.
+func (c Changes) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+func (c *Changes) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, c)
+	case string:
+		return json.Unmarshal([]byte(src), c)
+	case nil:
+		*c = nil
+		return nil
+	default:
+		return errors.New("unsupported type of changes")
+	}
+}
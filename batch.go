@@ -0,0 +1,81 @@
+package todo
+
+import "fmt"
+
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// MaxBatchOperations caps the operations of a batch, which all run in one
+// transaction.
+const MaxBatchOperations = 500
+
+// BatchOperation creates the Item, or applies the Update to or deletes the
+// item ItemId, which must be in the list of the batch.
+type BatchOperation struct {
+	Op     string           `json:"op" binding:"required"`
+	ItemId int              `json:"item_id"`
+	Item   *TodoItem        `json:"item"`
+	Update *UpdateItemInput `json:"update"`
+}
+
+func (o BatchOperation) Validate() error {
+	switch o.Op {
+	case BatchCreate:
+		if o.Item == nil || o.ItemId != 0 || o.Update != nil {
+			return fmt.Errorf("%w: create takes an item only", ErrValidation)
+		}
+		if o.Item.Title == "" {
+			return fmt.Errorf("%w: item title is required", ErrValidation)
+		}
+		return o.Item.Validate()
+	case BatchUpdate:
+		if o.ItemId == 0 || o.Update == nil || o.Item != nil {
+			return fmt.Errorf("%w: update takes an item_id and an update", ErrValidation)
+		}
+		return o.Update.Validate()
+	case BatchDelete:
+		if o.ItemId == 0 || o.Item != nil || o.Update != nil {
+			return fmt.Errorf("%w: delete takes an item_id only", ErrValidation)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: op must be create, update or delete", ErrValidation)
+	}
+}
+
+// BatchInput holds operations on the items of a list, which succeed or fail
+// together. With Partial, each of them succeeds or fails on its own instead.
+type BatchInput struct {
+	Operations []BatchOperation `json:"operations" binding:"required"`
+	Partial    bool             `json:"partial"`
+}
+
+// Validate checks every operation unless the batch is partial, where invalid
+// operations only fail themselves.
+func (i BatchInput) Validate() error {
+	if len(i.Operations) == 0 || len(i.Operations) > MaxBatchOperations {
+		return fmt.Errorf("%w: operations must have between 1 and %d entries", ErrValidation, MaxBatchOperations)
+	}
+	if i.Partial {
+		return nil
+	}
+	for n, op := range i.Operations {
+		if err := op.Validate(); err != nil {
+			return fmt.Errorf("operation %d: %w", n, err)
+		}
+	}
+	return nil
+}
+
+// BatchResult tells how an operation of a batch went. NextItemId is the next
+// occurrence of a repeating item marked done. Err is only set for failed
+// operations of a partial batch.
+type BatchResult struct {
+	Op         string `json:"op"`
+	ItemId     int    `json:"item_id,omitempty"`
+	NextItemId int    `json:"next_item_id,omitempty"`
+	Err        error  `json:"-"`
+}
@@ -0,0 +1,38 @@
+package todo
+
+import "fmt"
+
+// Components of the items in calendar feeds. Calendars without tasks only
+// show events.
+const (
+	ComponentTodo  = "vtodo"
+	ComponentEvent = "vevent"
+)
+
+// CalendarFeed holds the dated items of a user, or of one of their lists,
+// for an iCalendar subscription.
+type CalendarFeed struct {
+	Name  string
+	Items []TodoItem
+}
+
+// CalendarQuery holds the parameters of a feed, which covers all lists unless
+// ListId is set.
+type CalendarQuery struct {
+	ListId    *int   `form:"list_id"`
+	Component string `form:"component,default=vtodo"`
+}
+
+func (q CalendarQuery) Validate() error {
+	if q.Component != ComponentTodo && q.Component != ComponentEvent {
+		return fmt.Errorf("%w: component must be vtodo or vevent", ErrValidation)
+	}
+	return nil
+}
+
+// CalendarToken lets calendar clients fetch the feed of its user without
+// signing in. A user has one at most, and a new one replaces it.
+type CalendarToken struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
@@ -2,14 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/events"
 	"github.com/LittleMikle/ToDo_List/pkg/handler"
+	"github.com/LittleMikle/ToDo_List/pkg/migrate"
+	"github.com/LittleMikle/ToDo_List/pkg/reminder"
 	"github.com/LittleMikle/ToDo_List/pkg/repository"
 	"github.com/LittleMikle/ToDo_List/pkg/service"
+	"github.com/LittleMikle/ToDo_List/pkg/trash"
+	"github.com/LittleMikle/ToDo_List/pkg/webhook"
+	"github.com/LittleMikle/ToDo_List/schema"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
+	"io/fs"
 	"os"
 	"os/signal"
 	"syscall"
@@ -35,29 +43,122 @@ func main() {
 	}
 
 	err = godotenv.Load()
-	if err != nil {
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Info().Msg("No .env file, using the environment")
+	} else if err != nil {
 		log.Fatal().Msgf("error with .env file %s", err)
 	} else {
 		log.Info().Msg("Config load successful")
 	}
 
-	db, err := repository.NewPostgresDB(repository.Config{
+	dbConfig := repository.Config{
 		Host:     viper.GetString("db.host"),
 		Port:     viper.GetString("db.port"),
 		Username: viper.GetString("db.username"),
 		DBName:   viper.GetString("db.dbname"),
 		SSLMode:  viper.GetString("db.sslmode"),
 		Password: os.Getenv("DB_PASSWORD"),
-	})
+	}
+	db, err := repository.NewPostgresDB(dbConfig)
 	if err != nil {
-		log.Fatal().Msgf("failed with Postgres connection %w", err)
+		log.Fatal().Msgf("failed with Postgres connection %s", err)
 	} else {
 		log.Info().Msg("Connection to Postgres successful")
 	}
 
+	migrator, err := migrate.NewMigrator(db, schema.Migrations)
+	if err != nil {
+		log.Fatal().Msgf("failed with loading migrations %s", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = runMigrate(context.Background(), migrator, os.Args[2:])
+		db.Close()
+		if err != nil {
+			log.Fatal().Msgf("failed with migrate %s", err)
+		}
+		return
+	}
+
+	if viper.GetBool("db.auto_migrate") {
+		err = migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal().Msgf("failed with applying migrations %s", err)
+		}
+	}
+
+	var keyConfigs []service.KeyConfig
+	err = viper.UnmarshalKey("auth.keys", &keyConfigs)
+	if err != nil {
+		log.Fatal().Msgf("failed with JWT keys config %s", err)
+	}
+
+	keys, err := service.NewKeySet(viper.GetString("auth.active_kid"), keyConfigs)
+	if err != nil {
+		log.Fatal().Msgf("failed with loading JWT keys %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var broker events.Broker
+	bus := events.NewBus(viper.GetInt("events.buffer"))
+	broker = bus
+	if viper.GetBool("events.notify") {
+		pgBroker := events.NewPostgresBroker(db, dbConfig.DSN(), bus)
+		go pgBroker.Run(ctx)
+		broker = pgBroker
+	}
+
 	repos := repository.NewRepository(db)
-	services := service.NewService(repos)
-	handlers := handler.NewHandler(services)
+
+	legacySalt := os.Getenv("PASSWORD_SALT")
+	if legacySalt == "" {
+		legacy, err := repos.Authorization.HasLegacyPasswordHashes(ctx)
+		if err != nil {
+			log.Fatal().Msgf("failed with checking password hashes %s", err)
+		}
+		if legacy {
+			log.Fatal().Msg("PASSWORD_SALT is empty but users have legacy password hashes, see UPGRADING.md")
+		}
+	}
+
+	services := service.NewService(repos, service.AuthConfig{
+		Keys:       keys,
+		LegacySalt: legacySalt,
+	}, broker)
+	handlers := handler.NewHandler(services, viper.GetDuration("db.query_timeout"))
+
+	if viper.GetBool("reminders.enabled") {
+		scheduler := reminder.NewScheduler(repos.Reminder, newNotifier(), reminder.Config{
+			Interval:    viper.GetDuration("reminders.interval"),
+			CatchUp:     viper.GetDuration("reminders.catch_up"),
+			Lease:       viper.GetDuration("reminders.lease"),
+			BatchSize:   viper.GetInt("reminders.batch_size"),
+			MaxAttempts: viper.GetInt("reminders.max_attempts"),
+		})
+		go scheduler.Run(ctx)
+	}
+
+	if viper.GetBool("trash.purge_enabled") {
+		purger := trash.NewPurger(repos.Trash, trash.Config{
+			Interval:  viper.GetDuration("trash.purge_interval"),
+			Retention: viper.GetDuration("trash.retention"),
+		})
+		go purger.Run(ctx)
+	}
+
+	if viper.GetBool("webhooks.enabled") {
+		dispatcher := webhook.NewDispatcher(repos.WebhookDelivery, webhook.Config{
+			Interval:     viper.GetDuration("webhooks.interval"),
+			Lease:        viper.GetDuration("webhooks.lease"),
+			Timeout:      viper.GetDuration("webhooks.timeout"),
+			BatchSize:    viper.GetInt("webhooks.batch_size"),
+			MaxAttempts:  viper.GetInt("webhooks.max_attempts"),
+			DisableAfter: viper.GetInt("webhooks.disable_after"),
+		})
+		go dispatcher.Run(ctx)
+	}
 
 	srv := new(todo.Server)
 
@@ -76,13 +177,35 @@ func main() {
 
 	log.Info().Msg("Shutting down server successful")
 
-	err = srv.Shutdown(context.Background())
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), viper.GetDuration("shutdown_timeout"))
+	defer shutdownCancel()
+
+	err = srv.Shutdown(shutdownCtx)
 	if err != nil {
-		log.Error().Msgf("failed with shutting down %w", err)
+		log.Error().Msgf("failed with shutting down %s", err)
 	}
 	err = db.Close()
 	if err != nil {
-		log.Error().Msgf("failed with closing DB connection %w", err)
+		log.Error().Msgf("failed with closing DB connection %s", err)
+	}
+}
+
+func newNotifier() reminder.Notifier {
+	switch viper.GetString("reminders.notifier") {
+	case "webhook":
+		return reminder.NewWebhookNotifier(viper.GetString("reminders.webhook_url"))
+	case "smtp":
+		return reminder.NewSMTPNotifier(reminder.SMTPConfig{
+			Host:     viper.GetString("reminders.smtp.host"),
+			Port:     viper.GetString("reminders.smtp.port"),
+			From:     viper.GetString("reminders.smtp.from"),
+			Username: viper.GetString("reminders.smtp.username"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		})
+	default:
+		return reminder.NewLogNotifier()
 	}
 }
 
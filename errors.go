@@ -0,0 +1,15 @@
+package todo
+
+import "errors"
+
+// Domain errors of the repository and service layers. They are wrapped with
+// details on the way up and mapped to HTTP status codes by the handlers.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrForbidden  = errors.New("forbidden")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+	// ErrUnauthorized is returned for bad credentials and tokens, whose
+	// details are not shown to clients.
+	ErrUnauthorized = errors.New("unauthorized")
+)
@@ -0,0 +1,67 @@
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Label is a tag of its user, who can attach it to any item they can access.
+// Other users of the list do not see it.
+type Label struct {
+	Id        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Color     string    `json:"color" db:"color"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+const maxLabelName = 64
+
+var labelColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type LabelInput struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color" binding:"required"`
+}
+
+func (i LabelInput) Validate() error {
+	if err := validateLabelName(i.Name); err != nil {
+		return err
+	}
+	return validateLabelColor(i.Color)
+}
+
+type UpdateLabelInput struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+func (i UpdateLabelInput) Validate() error {
+	if i.Name == nil && i.Color == nil {
+		return fmt.Errorf("%w: update structure has no values", ErrValidation)
+	}
+	if i.Name != nil {
+		if err := validateLabelName(*i.Name); err != nil {
+			return err
+		}
+	}
+	if i.Color != nil {
+		return validateLabelColor(*i.Color)
+	}
+	return nil
+}
+
+func validateLabelName(name string) error {
+	if name == "" || len([]rune(name)) > maxLabelName {
+		return fmt.Errorf("%w: name must have between 1 and %d characters", ErrValidation, maxLabelName)
+	}
+	return nil
+}
+
+func validateLabelColor(color string) error {
+	if !labelColor.MatchString(color) {
+		return fmt.Errorf("%w: color must look like #a1b2c3", ErrValidation)
+	}
+	return nil
+}
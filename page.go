@@ -0,0 +1,88 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+// ListSorts, ItemSorts and AllItemSorts are the sorts of GetAll requests for
+// lists, the items of a list and the items across lists, which have no common
+// position. The first one is the default.
+var (
+	ListSorts    = []string{"id", "title", "created_at"}
+	ItemSorts    = []string{"position", "id", "title", "created_at"}
+	AllItemSorts = []string{"id", "title", "created_at"}
+)
+
+// ListQuery holds the paging, sorting and filtering parameters of a GetAll
+// request. Done and Labels only apply to items, which must have all of the
+// labels of the user named in Labels.
+type ListQuery struct {
+	Limit  int      `form:"limit,default=50"`
+	Cursor string   `form:"cursor"`
+	Sort   string   `form:"sort"`
+	Order  string   `form:"order,default=asc"`
+	Title  string   `form:"title"`
+	Done   *bool    `form:"done"`
+	Labels []string `form:"label"`
+}
+
+// PageQuery holds the paging parameters of feeds, which are always listed
+// newest first.
+type PageQuery struct {
+	Limit  int    `form:"limit,default=50"`
+	Cursor string `form:"cursor"`
+}
+
+type PageInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total"`
+}
+
+// WithDefaultSort returns the query sorted by the first of sorts if it has
+// no sort.
+func (q ListQuery) WithDefaultSort(sorts []string) ListQuery {
+	if q.Sort == "" {
+		q.Sort = sorts[0]
+	}
+	return q
+}
+
+func (q ListQuery) Validate(sorts []string) error {
+	if q.Limit < 1 || q.Limit > MaxPageLimit {
+		return fmt.Errorf("%w: limit must be between 1 and 100", ErrValidation)
+	}
+	if !containsString(sorts, q.Sort) {
+		return fmt.Errorf("%w: sort must be one of %s", ErrValidation, strings.Join(sorts, ", "))
+	}
+	if q.Order != "asc" && q.Order != "desc" {
+		return fmt.Errorf("%w: order must be asc or desc", ErrValidation)
+	}
+	for i, label := range q.Labels {
+		if containsString(q.Labels[:i], label) {
+			return fmt.Errorf("%w: label %s is given twice", ErrValidation, label)
+		}
+	}
+	return nil
+}
+
+func (q PageQuery) Validate() error {
+	if q.Limit < 1 || q.Limit > MaxPageLimit {
+		return fmt.Errorf("%w: limit must be between 1 and 100", ErrValidation)
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
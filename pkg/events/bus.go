@@ -0,0 +1,110 @@
+package events
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"sync"
+)
+
+const (
+	ItemCreated    = "item_created"
+	ItemUpdated    = "item_updated"
+	ItemDeleted    = "item_deleted"
+	ItemsReordered = "items_reordered"
+)
+
+// Event tells the subscribers of a list that its items changed. Item is left
+// out for deleted items, and both item fields for reorders, after which
+// clients reload the order.
+type Event struct {
+	Type   string         `json:"type"`
+	ListId int            `json:"list_id"`
+	ItemId int            `json:"item_id,omitempty"`
+	Item   *todo.TodoItem `json:"item,omitempty"`
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type Subscriber interface {
+	// Subscribe returns the events of a list and a func to stop receiving
+	// them. The channel is closed when the subscriber falls behind.
+	Subscribe(listId int) (<-chan Event, func())
+}
+
+type Broker interface {
+	Publisher
+	Subscriber
+}
+
+// Bus delivers events to the subscribers in this process.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan Event]struct{}
+	buffer      int
+}
+
+func NewBus(buffer int) *Bus {
+	return &Bus{
+		subscribers: make(map[int]map[chan Event]struct{}),
+		buffer:      buffer,
+	}
+}
+
+func (b *Bus) Subscribe(listId int) (<-chan Event, func()) {
+	ch := make(chan Event, b.buffer)
+
+	b.mu.Lock()
+	if b.subscribers[listId] == nil {
+		b.subscribers[listId] = make(map[chan Event]struct{})
+	}
+	b.subscribers[listId][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(listId, ch)
+	}
+}
+
+// Publish never blocks. A subscriber whose buffer is full is dropped instead
+// of silently missing events, so that its client reconnects and reloads.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.ListId] {
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Int("list_id", event.ListId).Msg("dropping slow events subscriber")
+			b.remove(event.ListId, ch)
+		}
+	}
+	return nil
+}
+
+func (b *Bus) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for listId, subscribers := range b.subscribers {
+		for ch := range subscribers {
+			b.remove(listId, ch)
+		}
+	}
+}
+
+func (b *Bus) remove(listId int, ch chan Event) {
+	if _, ok := b.subscribers[listId][ch]; !ok {
+		return
+	}
+	delete(b.subscribers[listId], ch)
+	if len(b.subscribers[listId]) == 0 {
+		delete(b.subscribers, listId)
+	}
+	close(ch)
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus(1)
+
+	events, unsubscribe := bus.Subscribe(1)
+	other, unsubscribeOther := bus.Subscribe(2)
+	defer unsubscribeOther()
+
+	assert.NoError(t, bus.Publish(context.Background(), Event{Type: ItemCreated, ListId: 1, ItemId: 3}))
+	assert.Equal(t, Event{Type: ItemCreated, ListId: 1, ItemId: 3}, <-events)
+	assert.Len(t, other, 0)
+
+	unsubscribe()
+	_, ok := <-events
+	assert.False(t, ok)
+
+	// unsubscribing twice is fine
+	unsubscribe()
+}
+
+func TestBus_PublishSlowSubscriber(t *testing.T) {
+	bus := NewBus(1)
+
+	events, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+
+	bus.Publish(context.Background(), Event{Type: ItemCreated, ListId: 1, ItemId: 3})
+	bus.Publish(context.Background(), Event{Type: ItemDeleted, ListId: 1, ItemId: 3})
+
+	assert.Equal(t, ItemCreated, (<-events).Type)
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestBus_DropAll(t *testing.T) {
+	bus := NewBus(1)
+
+	events, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+
+	bus.dropAll()
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.Empty(t, bus.subscribers)
+}
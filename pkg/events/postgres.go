@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const (
+	notifyChannel = "todo_events"
+	// maxPayload stays below the 8000 byte limit of NOTIFY payloads.
+	maxPayload = 7900
+)
+
+// PostgresBroker fans events out to every replica through LISTEN/NOTIFY.
+// Events published here reach the local Bus only once Postgres echoes them
+// back, so all replicas deliver them in the same order.
+type PostgresBroker struct {
+	db  *sqlx.DB
+	dsn string
+	bus *Bus
+}
+
+func NewPostgresBroker(db *sqlx.DB, dsn string, bus *Bus) *PostgresBroker {
+	return &PostgresBroker{db: db, dsn: dsn, bus: bus}
+}
+
+// Publish sends the event to all replicas. Items too large for a
+// notification are left out, clients then have to load them.
+func (b *PostgresBroker) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayload {
+		event.Item = nil
+		if payload, err = json.Marshal(event); err != nil {
+			return err
+		}
+	}
+
+	_, err = b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", notifyChannel, string(payload))
+	return err
+}
+
+func (b *PostgresBroker) Subscribe(listId int) (<-chan Event, func()) {
+	return b.bus.Subscribe(listId)
+}
+
+// Run listens for notifications and hands them to the local Bus until ctx is
+// cancelled.
+func (b *PostgresBroker) Run(ctx context.Context) {
+	listener := pq.NewListener(b.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Error().Err(err).Msg("failed with events listener")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(notifyChannel); err != nil {
+		log.Error().Err(err).Msg("failed to listen for events")
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			// nil after the connection was re-established: events sent in
+			// between are lost, so clients have to reconnect and reload
+			if n == nil {
+				b.bus.dropAll()
+				continue
+			}
+			b.deliver(ctx, n.Extra)
+		}
+	}
+}
+
+func (b *PostgresBroker) deliver(ctx context.Context, payload string) {
+	var event Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		log.Error().Err(err).Msg("failed with decoding event")
+		return
+	}
+	b.bus.Publish(ctx, event)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type getActivityResponse struct {
+	Data       []todo.Activity `json:"data"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Total      int             `json:"total"`
+}
+
+func (h *Handler) getListActivity(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var query todo.PageQuery
+	if err = c.ShouldBindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid query params")
+		return
+	}
+
+	activities, page, err := h.services.Activity.GetAll(c.Request.Context(), userId, listId, query)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getActivityResponse{
+		Data:       activities,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -15,7 +16,7 @@ import (
 // @Produce  json
 // @Param input body todo.User true "account info"
 // @Success 200 {integer} integer 1
-// @Failure 400,404 {object} errorResponse
+// @Failure 400,404,409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /auth/sign-up [post]
@@ -26,7 +27,11 @@ func (h *Handler) signUp(c *gin.Context) {
 		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
 		return
 	}
-	id, err := h.services.Authorization.CreateUser(input)
+	id, err := h.services.Authorization.CreateUser(c.Request.Context(), input)
+	if errors.Is(err, todo.ErrConflict) {
+		newServiceErrorResponse(c, err)
+		return
+	}
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, "service failure")
 		return
@@ -48,26 +53,109 @@ type signInInput struct {
 // @Accept  json
 // @Produce  json
 // @Param input body signInInput true "credentials"
-// @Success 200 {string} string "token"
-// @Failure 400,404 {object} errorResponse
+// @Success 200 {object} todo.Tokens
+// @Failure 400,401 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /auth/sign-in [post]
 func (h *Handler) signIn(c *gin.Context) {
 	var input signInInput
-
 	err := c.BindJSON(&input)
 	if err != nil {
 		log.Error().Err(err).Msg("failed with signIn JSON input:")
 		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	tokens, err := h.services.Authorization.GenerateToken(c.Request.Context(), input.Username, input.Password)
+	if errors.Is(err, todo.ErrUnauthorized) {
+		newErrorResponse(c, http.StatusUnauthorized, "invalid username or password")
+		return
 	}
-	token, err := h.services.Authorization.GenerateToken(input.Username, input.Password)
 	if err != nil {
 		log.Error().Err(err).Msg("failed with signIn generating token:")
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, http.StatusInternalServerError, "service failure")
 		return
 	}
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"id": token,
-	})
+	c.JSON(http.StatusOK, tokens)
+}
+
+type refreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// @Summary Refresh
+// @Tags auth
+// @Description rotate refresh token and issue a new token pair
+// @ID refresh
+// @Accept  json
+// @Produce  json
+// @Param input body refreshInput true "refresh token"
+// @Success 200 {object} todo.Tokens
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/refresh [post]
+func (h *Handler) refresh(c *gin.Context) {
+	var input refreshInput
+	err := c.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+	tokens, err := h.services.Authorization.RefreshToken(c.Request.Context(), input.RefreshToken)
+	if err != nil {
+		newRefreshErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary SignOut
+// @Tags auth
+// @Description revoke the session of a refresh token
+// @ID sign-out
+// @Accept  json
+// @Produce  json
+// @Param input body refreshInput true "refresh token"
+// @Success 200 {object} statusResponse
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/sign-out [post]
+func (h *Handler) signOut(c *gin.Context) {
+	var input refreshInput
+	err := c.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+	err = h.services.Authorization.RevokeToken(c.Request.Context(), input.RefreshToken)
+	if err != nil {
+		newRefreshErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// newRefreshErrorResponse hides why a refresh token was refused, and tells
+// failures of the service apart from it.
+func newRefreshErrorResponse(c *gin.Context, err error) {
+	if errors.Is(err, todo.ErrUnauthorized) {
+		log.Warn().Err(err).Msg("refresh token refused")
+		newErrorResponse(c, http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
+	log.Error().Err(err).Msg("failed with refresh token")
+	newErrorResponse(c, http.StatusInternalServerError, "service failure")
+}
+
+// @Summary JWKS
+// @Tags auth
+// @Description public keys for verifying access tokens
+// @ID jwks
+// @Produce  json
+// @Success 200 {object} todo.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) jwks(c *gin.Context) {
+	c.JSON(http.StatusOK, h.services.Authorization.JWKS())
 }
@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/LittleMikle/ToDo_List/pkg/service"
 	mock_service "github.com/LittleMikle/ToDo_List/pkg/service/mocks"
@@ -33,7 +34,7 @@ func TestHandler_signUp(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior: func(s *mock_service.MockAuthorization, user todo.User) {
-				s.EXPECT().CreateUser(user).Return(1, nil)
+				s.EXPECT().CreateUser(gomock.Any(), user).Return(1, nil)
 			},
 			expectedStatusCode:  200,
 			expectedRequestBody: `{"id":1}`,
@@ -61,11 +62,25 @@ func TestHandler_signUp(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior: func(s *mock_service.MockAuthorization, user todo.User) {
-				s.EXPECT().CreateUser(user).Return(1, errors.New("service failure"))
+				s.EXPECT().CreateUser(gomock.Any(), user).Return(1, errors.New("service failure"))
 			},
 			expectedStatusCode:  500,
 			expectedRequestBody: `{"message":"service failure"}`,
 		},
+		{
+			name:      "Username Taken",
+			inputBody: `{"name":"Test","username":"test","password":"qwerty"}`,
+			inputUser: todo.User{
+				Name:     "Test",
+				Username: "test",
+				Password: "qwerty",
+			},
+			mockBehavior: func(s *mock_service.MockAuthorization, user todo.User) {
+				s.EXPECT().CreateUser(gomock.Any(), user).Return(0, fmt.Errorf("%w: username is already taken", todo.ErrConflict))
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"message":"conflict: username is already taken","code":"conflict"}`,
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -80,7 +95,7 @@ func TestHandler_signUp(t *testing.T) {
 			services := &service.Service{
 				Authorization: auth,
 			}
-			handler := NewHandler(services)
+			handler := NewHandler(services, 0)
 
 			// Test server
 			r := gin.New()
@@ -101,3 +116,250 @@ func TestHandler_signUp(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_signIn(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"username":"test","password":"qwerty"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().GenerateToken(gomock.Any(), "test", "qwerty").Return(todo.Tokens{
+					AccessToken:  "access",
+					RefreshToken: "1.secret",
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"access_token":"access","refresh_token":"1.secret"}`,
+		},
+		{
+			name:      "Invalid Credentials",
+			inputBody: `{"username":"test","password":"wrong"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().GenerateToken(gomock.Any(), "test", "wrong").
+					Return(todo.Tokens{}, fmt.Errorf("%w: invalid username or password", todo.ErrUnauthorized))
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"message":"invalid username or password"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"username":"test","password":"qwerty"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().GenerateToken(gomock.Any(), "test", "qwerty").
+					Return(todo.Tokens{}, errors.New("failed to GetUser: pq: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"service failure"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{
+				Authorization: auth,
+			}
+			handler := NewHandler(services, 0)
+
+			r := gin.New()
+			r.POST("/sign-in", handler.signIn)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/sign-in",
+				bytes.NewBufferString(testCase.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_refresh(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization, refreshToken string)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		refreshToken        string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:         "OK",
+			inputBody:    `{"refresh_token":"1.secret"}`,
+			refreshToken: "1.secret",
+			mockBehavior: func(s *mock_service.MockAuthorization, refreshToken string) {
+				s.EXPECT().RefreshToken(gomock.Any(), refreshToken).Return(todo.Tokens{
+					AccessToken:  "access",
+					RefreshToken: "1.rotated",
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"access_token":"access","refresh_token":"1.rotated"}`,
+		},
+		{
+			name:                "Empty Token",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_service.MockAuthorization, refreshToken string) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid input body"}`,
+		},
+		{
+			name:         "Reused Token",
+			inputBody:    `{"refresh_token":"1.secret"}`,
+			refreshToken: "1.secret",
+			mockBehavior: func(s *mock_service.MockAuthorization, refreshToken string) {
+				s.EXPECT().RefreshToken(gomock.Any(), refreshToken).
+					Return(todo.Tokens{}, fmt.Errorf("%w: refresh token reuse detected, session revoked", todo.ErrUnauthorized))
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"message":"invalid refresh token"}`,
+		},
+		{
+			name:         "Service Failure",
+			inputBody:    `{"refresh_token":"1.secret"}`,
+			refreshToken: "1.secret",
+			mockBehavior: func(s *mock_service.MockAuthorization, refreshToken string) {
+				s.EXPECT().RefreshToken(gomock.Any(), refreshToken).
+					Return(todo.Tokens{}, errors.New("failed to get session: pq: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"service failure"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(auth, testCase.refreshToken)
+
+			services := &service.Service{
+				Authorization: auth,
+			}
+			handler := NewHandler(services, 0)
+
+			// Test server
+			r := gin.New()
+			r.POST("/refresh", handler.refresh)
+
+			//Test request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/refresh",
+				bytes.NewBufferString(testCase.inputBody))
+
+			// Perform request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_signOut(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().RevokeToken(gomock.Any(), "1.secret").Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name: "Unknown Session",
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().RevokeToken(gomock.Any(), "1.secret").
+					Return(fmt.Errorf("%w: session not found", todo.ErrUnauthorized))
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"message":"invalid refresh token"}`,
+		},
+		{
+			name: "Service Failure",
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().RevokeToken(gomock.Any(), "1.secret").Return(errors.New("pq: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"service failure"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{
+				Authorization: auth,
+			}
+			handler := NewHandler(services, 0)
+
+			r := gin.New()
+			r.POST("/sign-out", handler.signOut)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/sign-out",
+				bytes.NewBufferString(`{"refresh_token":"1.secret"}`))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_jwks(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mock_service.NewMockAuthorization(c)
+	auth.EXPECT().JWKS().Return(todo.JWKS{Keys: []todo.JWK{
+		{Kty: "OKP", Use: "sig", Kid: "ed-1", Alg: "EdDSA", Crv: "Ed25519", X: "key"},
+	}})
+
+	handler := NewHandler(&service.Service{Authorization: auth}, 0)
+
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", handler.jwks)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"keys":[{"kty":"OKP","use":"sig","kid":"ed-1","alg":"EdDSA","crv":"Ed25519","x":"key"}]}`, w.Body.String())
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/ical"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+const calendarPath = "/calendar/"
+
+func (h *Handler) createCalendarToken(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	token, err := h.services.Calendar.CreateToken(c.Request.Context(), userId)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, todo.CalendarToken{Token: token, URL: calendarURL(c, token)})
+}
+
+func (h *Handler) deleteCalendarToken(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	if err = h.services.Calendar.DeleteToken(c.Request.Context(), userId); err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// calendarFeed serves the feed of the token in the path, as calendar clients
+// can't send an Authorization header.
+func (h *Handler) calendarFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var query todo.CalendarQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid query params")
+		return
+	}
+
+	feed, err := h.services.Calendar.Feed(c.Request.Context(), token, query)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	var b bytes.Buffer
+	if err = ical.Encode(&b, feed, query.Component); err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, ical.ContentType, b.Bytes())
+}
+
+// calendarURL returns the address of the feed on the host the request was
+// sent to.
+func calendarURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return fmt.Sprintf("%s://%s%s%s.ics", scheme, c.Request.Host, calendarPath, token)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/service"
+	mock_service "github.com/LittleMikle/ToDo_List/pkg/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_calendarFeed(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockCalendar)
+
+	listId := 3
+
+	testTable := []struct {
+		name                string
+		path                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			path: "/calendar/secret.ics?list_id=3",
+			mockBehavior: func(s *mock_service.MockCalendar) {
+				query := todo.CalendarQuery{ListId: &listId, Component: todo.ComponentTodo}
+				s.EXPECT().Feed(gomock.Any(), "secret", query).Return(todo.CalendarFeed{Name: "Release"}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/calendar; charset=utf-8",
+			expectedRequestBody: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//LittleMikle//ToDo_List//EN\r\nCALSCALE:GREGORIAN\r\n" +
+				"X-WR-CALNAME:Release\r\nEND:VCALENDAR\r\n",
+		},
+		{
+			name: "Unknown Token",
+			path: "/calendar/other.ics",
+			mockBehavior: func(s *mock_service.MockCalendar) {
+				query := todo.CalendarQuery{Component: todo.ComponentTodo}
+				s.EXPECT().Feed(gomock.Any(), "other", query).Return(todo.CalendarFeed{}, fmt.Errorf("calendar feed %w", todo.ErrNotFound))
+			},
+			expectedStatusCode:  404,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedRequestBody: `{"message":"calendar feed not found","code":"not_found"}`,
+		},
+		{
+			name:                "Invalid List Id",
+			path:                "/calendar/secret.ics?list_id=abc",
+			mockBehavior:        func(s *mock_service.MockCalendar) {},
+			expectedStatusCode:  400,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedRequestBody: `{"message":"invalid query params"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			calendar := mock_service.NewMockCalendar(c)
+			testCase.mockBehavior(calendar)
+
+			services := &service.Service{Calendar: calendar}
+			handler := NewHandler(services, 0)
+
+			r := gin.New()
+			r.GET("/calendar/:token", handler.calendarFeed)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", testCase.path, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// streamHeartbeat is how often an idle stream is pinged, which also checks
+// that the user still has access to the list.
+const streamHeartbeat = 30 * time.Second
+
+func (h *Handler) streamListEvents(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	ctx := c.Request.Context()
+	events, unsubscribe, err := h.services.Events.Subscribe(ctx, userId, listId)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+	defer unsubscribe()
+
+	// streams outlive the write timeout of the server
+	if err = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debug().Err(err).Msg("failed with clearing stream write deadline")
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			if _, err := h.services.TodoList.GetById(ctx, userId, listId); err != nil {
+				return false
+			}
+			c.SSEvent("ping", "")
+			return true
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/events"
+	"github.com/LittleMikle/ToDo_List/pkg/service"
+	mock_service "github.com/LittleMikle/ToDo_List/pkg/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+// streamRecorder is a ResponseRecorder that gin can stream to.
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r streamRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func TestHandler_streamListEvents(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockEvents, userId, listId int)
+
+	testTable := []struct {
+		name                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockEvents, userId, listId int) {
+				ch := make(chan events.Event, 1)
+				ch <- events.Event{Type: events.ItemDeleted, ListId: listId, ItemId: 2}
+				close(ch)
+				s.EXPECT().Subscribe(gomock.Any(), userId, listId).Return((<-chan events.Event)(ch), func() {}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: "event:item_deleted\ndata:{\"type\":\"item_deleted\",\"list_id\":1,\"item_id\":2}\n\n",
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func(s *mock_service.MockEvents, userId, listId int) {
+				s.EXPECT().Subscribe(gomock.Any(), userId, listId).Return(nil, nil, fmt.Errorf("list %w", todo.ErrNotFound))
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"message":"list not found","code":"not_found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			subscriptions := mock_service.NewMockEvents(c)
+			testCase.mockBehavior(subscriptions, 1, 1)
+
+			services := &service.Service{Events: subscriptions}
+			handler := NewHandler(services, 0)
+
+			r := gin.New()
+			r.GET("/lists/:id/events", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.streamListEvents)
+
+			w := streamRecorder{httptest.NewRecorder()}
+			req := httptest.NewRequest("GET", "/lists/1/events", nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/LittleMikle/ToDo_List/pkg/service"
 	"github.com/gin-gonic/gin"
+	"time"
 
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
@@ -11,12 +12,16 @@ import (
 )
 
 type Handler struct {
-	services *service.Service
+	services     *service.Service
+	queryTimeout time.Duration
 }
 
-func NewHandler(services *service.Service) *Handler {
+// NewHandler creates the handlers. A positive queryTimeout bounds the time
+// a request may spend in the database.
+func NewHandler(services *service.Service, queryTimeout time.Duration) *Handler {
 	return &Handler{
-		services: services,
+		services:     services,
+		queryTimeout: queryTimeout,
 	}
 }
 
@@ -24,36 +29,95 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/.well-known/jwks.json", h.jwks)
+	// calendar clients authenticate with the token in the path
+	router.GET(calendarPath+":token", h.withQueryTimeout, h.calendarFeed)
 
-	auth := router.Group("/auth")
+	auth := router.Group("/auth", h.withQueryTimeout)
 	{
 		auth.POST("/sign-up", h.signUp)
 		auth.POST("/sign-in", h.signIn)
+		auth.POST("/refresh", h.refresh)
+		auth.POST("/sign-out", h.signOut)
 	}
 
-	api := router.Group("/api", h.userIdentity)
+	api := router.Group("/api", h.withQueryTimeout, h.userIdentity)
 	{
 		lists := api.Group("/lists")
 		{
 			lists.POST("/", h.createList)
 			lists.GET("/", h.getAllLists)
+			lists.POST("/import", h.importList)
 			lists.GET("/:id", h.getListById)
 			lists.PUT("/:id", h.updateList)
 			lists.DELETE("/:id", h.deleteList)
+			lists.POST("/:id/restore", h.restoreList)
+			lists.GET("/:id/activity", h.getListActivity)
+			lists.GET("/:id/export", h.exportList)
 
 			items := lists.Group(":id/items")
 			{
 				items.POST("/", h.createItem)
 				items.GET("/", h.getAllItems)
+				items.PUT("/order", h.reorderItems)
+				items.POST("/batch", h.batchItems)
+				items.GET("/tree", h.getItemTree)
+			}
+
+			collaborators := lists.Group(":id/collaborators")
+			{
+				collaborators.POST("/", h.shareList)
+				collaborators.GET("/", h.getAllCollaborators)
+				collaborators.DELETE("/:user_id", h.removeCollaborator)
 			}
 		}
 
 		items := api.Group("items")
 		{
+			items.GET("/", h.getAllUserItems)
+			items.GET("/overdue", h.getOverdueItems)
+			items.GET("/due-this-week", h.getItemsDueThisWeek)
 			items.GET("/:id", h.getItemById)
+			items.GET("/:id/subtasks", h.getSubtasks)
 			items.PUT("/:id", h.updateItem)
 			items.DELETE("/:id", h.deleteItem)
+			items.POST("/:id/restore", h.restoreItem)
+			items.POST("/:id/move", h.moveItem)
+			items.POST("/:id/copy", h.copyItem)
+			items.PUT("/:id/recurrence", h.setItemRecurrence)
+			items.DELETE("/:id/recurrence", h.stopItemRecurrence)
+			items.GET("/:id/labels", h.getItemLabels)
+			items.PUT("/:id/labels/:label_id", h.attachLabel)
+			items.DELETE("/:id/labels/:label_id", h.detachLabel)
+		}
+
+		labels := api.Group("/labels")
+		{
+			labels.POST("/", h.createLabel)
+			labels.GET("/", h.getAllLabels)
+			labels.GET("/:id", h.getLabelById)
+			labels.PUT("/:id", h.updateLabel)
+			labels.DELETE("/:id", h.deleteLabel)
 		}
+
+		api.GET("/trash", h.getTrash)
+		api.GET("/search", h.search)
+
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.POST("/", h.createWebhook)
+			webhooks.GET("/", h.getAllWebhooks)
+			webhooks.GET("/:id", h.getWebhookById)
+			webhooks.PUT("/:id", h.updateWebhook)
+			webhooks.DELETE("/:id", h.deleteWebhook)
+			webhooks.GET("/:id/deliveries", h.getWebhookDeliveries)
+		}
+
+		api.POST("/calendar/token", h.createCalendarToken)
+		api.DELETE("/calendar/token", h.deleteCalendarToken)
 	}
+
+	// streams stay open far longer than the query timeout
+	router.GET("/api/lists/:id/events", h.userIdentity, h.streamListEvents)
 	return router
 }
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/service"
+	mock_service "github.com/LittleMikle/ToDo_List/pkg/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_batchItems(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTodoItem)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"operations":[{"op":"create","item":{"title":"first"}},{"op":"delete","item_id":4}]}`,
+			mockBehavior: func(s *mock_service.MockTodoItem) {
+				s.EXPECT().Batch(gomock.Any(), 1, 3, todo.BatchInput{Operations: []todo.BatchOperation{
+					{Op: todo.BatchCreate, Item: &todo.TodoItem{Title: "first"}},
+					{Op: todo.BatchDelete, ItemId: 4},
+				}}).Return([]todo.BatchResult{{Op: todo.BatchCreate, ItemId: 7}, {Op: todo.BatchDelete, ItemId: 4}}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"data":[{"op":"create","item_id":7,"status":200},` +
+				`{"op":"delete","item_id":4,"status":200}]}`,
+		},
+		{
+			name:      "Partial",
+			inputBody: `{"operations":[{"op":"delete","item_id":4},{"op":"delete","item_id":5}],"partial":true}`,
+			mockBehavior: func(s *mock_service.MockTodoItem) {
+				s.EXPECT().Batch(gomock.Any(), 1, 3, gomock.Any()).Return([]todo.BatchResult{
+					{Op: todo.BatchDelete, ItemId: 4, Err: fmt.Errorf("item %w", todo.ErrNotFound)},
+					{Op: todo.BatchDelete, ItemId: 5, Err: errors.New("pq: deadlock detected")},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedRequestBody: `{"data":[{"op":"delete","item_id":4,"status":404,"error":{"message":"item not found","code":"not_found"}},` +
+				`{"op":"delete","item_id":5,"status":500,"error":{"message":"internal server error","code":"internal_error"}}]}`,
+		},
+		{
+			name:      "Failed Operation",
+			inputBody: `{"operations":[{"op":"update","item_id":4,"update":{"priority":7}}]}`,
+			mockBehavior: func(s *mock_service.MockTodoItem) {
+				s.EXPECT().Batch(gomock.Any(), 1, 3, gomock.Any()).
+					Return(nil, fmt.Errorf("operation 0: %w: priority must be between 0 and 3", todo.ErrValidation))
+			},
+			expectedStatusCode:  422,
+			expectedRequestBody: `{"message":"operation 0: validation failed: priority must be between 0 and 3","code":"validation_failed"}`,
+		},
+		{
+			name:                "Missing Operations",
+			inputBody:           `{"partial":true}`,
+			mockBehavior:        func(s *mock_service.MockTodoItem) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"Key: 'BatchInput.Operations' Error:Field validation for 'Operations' failed on the 'required' tag"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			items := mock_service.NewMockTodoItem(c)
+			testCase.mockBehavior(items)
+
+			services := &service.Service{TodoItem: items}
+			handler := NewHandler(services, 0)
+
+			r := gin.New()
+			r.POST("/lists/:id/items/batch", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.batchItems)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/lists/3/items/batch", bytes.NewBufferString(testCase.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"bytes"
+	"fmt"
 	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/transfer"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
@@ -20,9 +23,9 @@ func (h *Handler) createList(c *gin.Context) {
 		return
 	}
 
-	id, err := h.services.TodoList.Create(userId, input)
+	id, err := h.services.TodoList.Create(c.Request.Context(), userId, input)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, map[string]interface{}{
@@ -32,7 +35,9 @@ func (h *Handler) createList(c *gin.Context) {
 }
 
 type getAllListsResponse struct {
-	Data []todo.TodoList `json:"data"`
+	Data       []todo.TodoList `json:"data"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Total      int             `json:"total"`
 }
 
 func (h *Handler) getAllLists(c *gin.Context) {
@@ -41,14 +46,22 @@ func (h *Handler) getAllLists(c *gin.Context) {
 		return
 	}
 
-	lists, err := h.services.TodoList.GetAll(userId)
+	var query todo.ListQuery
+	if err = c.ShouldBindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid query params")
+		return
+	}
+
+	lists, page, err := h.services.TodoList.GetAll(c.Request.Context(), userId, query)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, getAllListsResponse{
-		Data: lists,
+		Data:       lists,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
 	})
 }
 
@@ -64,9 +77,9 @@ func (h *Handler) getListById(c *gin.Context) {
 		return
 	}
 
-	list, err := h.services.TodoList.GetById(userId, id)
+	list, err := h.services.TodoList.GetById(c.Request.Context(), userId, id)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(c, err)
 		return
 	}
 
@@ -76,7 +89,6 @@ func (h *Handler) getListById(c *gin.Context) {
 func (h *Handler) updateList(c *gin.Context) {
 	userId, err := getUserId(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -92,8 +104,8 @@ func (h *Handler) updateList(c *gin.Context) {
 		return
 	}
 
-	if err = h.services.TodoList.Update(userId, id, input); err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+	if err = h.services.TodoList.Update(c.Request.Context(), userId, id, input); err != nil {
+		newServiceErrorResponse(c, err)
 		return
 	}
 
@@ -112,9 +124,9 @@ func (h *Handler) deleteList(c *gin.Context) {
 		return
 	}
 
-	err = h.services.TodoList.Delete(userId, id)
+	err = h.services.TodoList.Delete(c.Request.Context(), userId, id)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newServiceErrorResponse(c, err)
 		return
 	}
 
@@ -122,3 +134,108 @@ func (h *Handler) deleteList(c *gin.Context) {
 		Status: "ok",
 	})
 }
+
+func (h *Handler) restoreList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	err = h.services.TodoList.Restore(c.Request.Context(), userId, id)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// transferQuery holds the parameters of exports and imports. The title of an
+// import replaces the one in the file, which CSV and todo.txt files lack.
+type transferQuery struct {
+	Format string `form:"format,default=json"`
+	Title  string `form:"title"`
+}
+
+// maxImportSize caps the size of imported files.
+const maxImportSize = 1 << 20
+
+func (h *Handler) exportList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var query transferQuery
+	if err = c.ShouldBindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid query params")
+		return
+	}
+	if err = todo.ValidateFormat(query.Format); err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	list, err := h.services.TodoList.Export(c.Request.Context(), userId, listId)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	var b bytes.Buffer
+	if err = transfer.Encode(&b, query.Format, list); err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="list-%d.%s"`, listId, transfer.Extensions[query.Format]))
+	c.Data(http.StatusOK, transfer.ContentTypes[query.Format], b.Bytes())
+}
+
+func (h *Handler) importList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var query transferQuery
+	if err = c.ShouldBindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid query params")
+		return
+	}
+	if err = todo.ValidateFormat(query.Format); err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	list, err := transfer.Decode(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize), query.Format)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+	if query.Title != "" {
+		list.Title = query.Title
+	}
+
+	id, err := h.services.TodoList.Import(c.Request.Context(), userId, list)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
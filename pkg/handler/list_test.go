@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/service"
+	mock_service "github.com/LittleMikle/ToDo_List/pkg/service/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestHandler_getListById(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTodoList, userId, listId int)
+
+	testTable := []struct {
+		name                string
+		listId              string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:   "OK",
+			listId: "1",
+			mockBehavior: func(s *mock_service.MockTodoList, userId, listId int) {
+				s.EXPECT().GetById(gomock.Any(), userId, listId).Return(todo.TodoList{Id: 1, Title: "title"}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":1,"title":"title","description":"","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:                "Invalid Id",
+			listId:              "abc",
+			mockBehavior:        func(s *mock_service.MockTodoList, userId, listId int) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"message":"invalid id param"}`,
+		},
+		{
+			name:   "Not Found",
+			listId: "404",
+			mockBehavior: func(s *mock_service.MockTodoList, userId, listId int) {
+				s.EXPECT().GetById(gomock.Any(), userId, listId).Return(todo.TodoList{}, fmt.Errorf("list %w", todo.ErrNotFound))
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"message":"list not found","code":"not_found"}`,
+		},
+		{
+			name:   "Service Failure",
+			listId: "1",
+			mockBehavior: func(s *mock_service.MockTodoList, userId, listId int) {
+				s.EXPECT().GetById(gomock.Any(), userId, listId).Return(todo.TodoList{}, errors.New("pq: connection refused"))
+			},
+			expectedStatusCode:  500,
+			expectedRequestBody: `{"message":"internal server error","code":"internal_error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			lists := mock_service.NewMockTodoList(c)
+			listId, _ := strconv.Atoi(testCase.listId)
+			testCase.mockBehavior(lists, 1, listId)
+
+			services := &service.Service{TodoList: lists}
+			handler := NewHandler(services, 0)
+
+			r := gin.New()
+			r.GET("/lists/:id", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.getListById)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/lists/"+testCase.listId, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_exportList(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTodoList)
+
+	testTable := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedRequestBody string
+	}{
+		{
+			name:  "OK",
+			query: "?format=md",
+			mockBehavior: func(s *mock_service.MockTodoList) {
+				s.EXPECT().Export(gomock.Any(), 1, 3).Return(todo.ListExport{
+					Title: "Release", Items: []todo.ExportItem{{Title: "Tag", Done: true}},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/markdown; charset=utf-8",
+			expectedRequestBody: "# Release\n\n- [x] Tag\n",
+		},
+		{
+			name:                "Unknown Format",
+			query:               "?format=xml",
+			mockBehavior:        func(s *mock_service.MockTodoList) {},
+			expectedStatusCode:  422,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedRequestBody: `{"message":"validation failed: format must be json, csv, md or todotxt","code":"validation_failed"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			lists := mock_service.NewMockTodoList(c)
+			testCase.mockBehavior(lists)
+
+			services := &service.Service{TodoList: lists}
+			handler := NewHandler(services, 0)
+
+			r := gin.New()
+			r.GET("/lists/:id/export", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.exportList)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/lists/3/export"+testCase.query, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -35,7 +36,7 @@ func (h *Handler) userIdentity(c *gin.Context) {
 		return
 	}
 
-	userId, err := h.services.Authorization.ParseToken(headerParts[1])
+	userId, err := h.services.Authorization.ParseToken(c.Request.Context(), headerParts[1])
 	if err != nil {
 		newErrorResponse(c, http.StatusUnauthorized, "failed parse token")
 		return
@@ -44,6 +45,20 @@ func (h *Handler) userIdentity(c *gin.Context) {
 	c.Set(userCtx, userId)
 }
 
+// withQueryTimeout puts a deadline on the request context, which cancels the
+// queries of the request together with a client disconnect.
+func (h *Handler) withQueryTimeout(c *gin.Context) {
+	if h.queryTimeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.queryTimeout)
+	defer cancel()
+
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
 func getUserId(c *gin.Context) (int, error) {
 	id, ok := c.Get(userCtx)
 	if !ok {
@@ -10,6 +10,7 @@ import (
 	"github.com/magiconair/properties/assert"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestHandler_userIdentity(t *testing.T) {
@@ -30,7 +31,7 @@ func TestHandler_userIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
-				s.EXPECT().ParseToken(token).Return(1, nil)
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(1, nil)
 			},
 			expectedStatusCode:   200,
 			expectedResponseBody: "1",
@@ -64,7 +65,7 @@ func TestHandler_userIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
-				s.EXPECT().ParseToken(token).Return(1, errors.New("failed parse token"))
+				s.EXPECT().ParseToken(gomock.Any(), token).Return(1, errors.New("failed parse token"))
 			},
 			expectedStatusCode:   401,
 			expectedResponseBody: `{"message":"failed parse token"}`,
@@ -83,7 +84,7 @@ func TestHandler_userIdentity(t *testing.T) {
 			services := &service.Service{
 				Authorization: auth,
 			}
-			handler := NewHandler(services)
+			handler := NewHandler(services, 0)
 
 			// Test Server
 			r := gin.New()
@@ -106,3 +107,41 @@ func TestHandler_userIdentity(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_withQueryTimeout(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		queryTimeout         time.Duration
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Deadline",
+			queryTimeout:         time.Second,
+			expectedResponseBody: "true",
+		},
+		{
+			name:                 "Disabled",
+			expectedResponseBody: "false",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewHandler(&service.Service{}, testCase.queryTimeout)
+
+			r := gin.New()
+			r.GET("/protected", handler.withQueryTimeout, func(c *gin.Context) {
+				_, ok := c.Request.Context().Deadline()
+				c.String(200, fmt.Sprintf("%t", ok))
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/protected", nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, 200)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
@@ -1,19 +1,64 @@
 package handler
 
 import (
+	"errors"
+	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"net/http"
 )
 
 type errorResponse struct {
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
 
 type statusResponse struct {
 	Status string `json:"status"`
 }
 
+// Machine-readable codes of errorResponse.
+const (
+	codeNotFound         = "not_found"
+	codeForbidden        = "forbidden"
+	codeConflict         = "conflict"
+	codeValidationFailed = "validation_failed"
+	codeInternal         = "internal_error"
+)
+
 func newErrorResponse(c *gin.Context, statusCode int, message string) {
 	log.Error().Msg(message)
-	c.AbortWithStatusJSON(statusCode, errorResponse{message})
+	c.AbortWithStatusJSON(statusCode, errorResponse{Message: message})
+}
+
+// newServiceErrorResponse maps an error of the service layer to a status code.
+// Unknown errors are logged but not shown, as they may carry SQL details.
+func newServiceErrorResponse(c *gin.Context, err error) {
+	statusCode, response := serviceError(err)
+	log.Error().Err(err).Str("code", response.Code).Msg("request failed")
+	c.AbortWithStatusJSON(statusCode, response)
+}
+
+func serviceError(err error) (int, errorResponse) {
+	statusCode, code := errorStatus(err)
+	message := err.Error()
+	if statusCode == http.StatusInternalServerError {
+		message = "internal server error"
+	}
+	return statusCode, errorResponse{Message: message, Code: code}
+}
+
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, todo.ErrNotFound):
+		return http.StatusNotFound, codeNotFound
+	case errors.Is(err, todo.ErrForbidden):
+		return http.StatusForbidden, codeForbidden
+	case errors.Is(err, todo.ErrConflict):
+		return http.StatusConflict, codeConflict
+	case errors.Is(err, todo.ErrValidation):
+		return http.StatusUnprocessableEntity, codeValidationFailed
+	default:
+		return http.StatusInternalServerError, codeInternal
+	}
 }
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/magiconair/properties/assert"
+	"testing"
+)
+
+func TestErrorStatus(t *testing.T) {
+	testTable := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedName string
+	}{
+		{name: "Not Found", err: fmt.Errorf("item %w", todo.ErrNotFound), expectedCode: 404, expectedName: codeNotFound},
+		{name: "Forbidden", err: fmt.Errorf("%w: read-only", todo.ErrForbidden), expectedCode: 403, expectedName: codeForbidden},
+		{name: "Conflict", err: fmt.Errorf("%w: taken", todo.ErrConflict), expectedCode: 409, expectedName: codeConflict},
+		{name: "Validation", err: fmt.Errorf("%w: bad", todo.ErrValidation), expectedCode: 422, expectedName: codeValidationFailed},
+		{name: "Unknown", err: errors.New("boom"), expectedCode: 500, expectedName: codeInternal},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			code, name := errorStatus(testCase.err)
+			assert.Equal(t, testCase.expectedCode, code)
+			assert.Equal(t, testCase.expectedName, name)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type searchResponse struct {
+	Data []todo.SearchResult `json:"data"`
+}
+
+func (h *Handler) search(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var query todo.SearchQuery
+	if err = c.ShouldBindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid query params")
+		return
+	}
+
+	results, err := h.services.Search.Search(c.Request.Context(), userId, query)
+	if err != nil {
+		newServiceErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, searchResponse{
+		Data: results,
+	})
+}
@@ -0,0 +1,154 @@
+// Package ical encodes calendar feeds as iCalendar (RFC 5545) for calendar
+// clients to subscribe to. Items are tasks (VTODO) due at their due time, or
+// events (VEVENT) starting then for clients that don't show tasks, with done
+// events marked in their summary.
+package ical
+
+import (
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+	prodId      = "-//LittleMikle//ToDo_List//EN"
+	uidDomain   = "todo-list"
+	timeLayout  = "20060102T150405Z"
+	// maxLineLength is the length of content lines in octets, longer ones
+	// are folded.
+	maxLineLength = 75
+	doneMark      = "✓ "
+)
+
+// priorities maps the priorities of items to the ones of iCalendar, where 1
+// is the highest and 9 the lowest.
+var priorities = map[int]int{
+	todo.PriorityHigh:   1,
+	todo.PriorityMedium: 5,
+	todo.PriorityLow:    9,
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// Encode writes the feed as a calendar of items of the given component.
+func Encode(w io.Writer, feed todo.CalendarFeed, component string) error {
+	e := encoder{w: w}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + prodId)
+	e.line("CALSCALE:GREGORIAN")
+	e.line("X-WR-CALNAME:" + escape(feed.Name))
+	for _, item := range feed.Items {
+		if item.DueAt == nil {
+			continue
+		}
+		if component == todo.ComponentEvent {
+			e.event(item)
+		} else {
+			e.todo(item)
+		}
+	}
+	e.line("END:VCALENDAR")
+	return e.err
+}
+
+// UID identifies the item across feeds and over time, so clients update
+// their copy instead of adding another one.
+func UID(itemId int) string {
+	return fmt.Sprintf("item-%d@%s", itemId, uidDomain)
+}
+
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) todo(item todo.TodoItem) {
+	e.line("BEGIN:VTODO")
+	e.common(item, item.Title)
+	e.line("DUE:" + formatTime(*item.DueAt))
+	if item.Done {
+		e.line("STATUS:COMPLETED")
+		e.line("COMPLETED:" + formatTime(item.UpdatedAt))
+		e.line("PERCENT-COMPLETE:100")
+	} else {
+		e.line("STATUS:NEEDS-ACTION")
+	}
+	if priority, ok := priorities[item.Priority]; ok {
+		e.line(fmt.Sprintf("PRIORITY:%d", priority))
+	}
+	e.alarm(item)
+	e.line("END:VTODO")
+}
+
+func (e *encoder) event(item todo.TodoItem) {
+	summary := item.Title
+	if item.Done {
+		summary = doneMark + summary
+	}
+	e.line("BEGIN:VEVENT")
+	e.common(item, summary)
+	e.line("DTSTART:" + formatTime(*item.DueAt))
+	e.line("DTEND:" + formatTime(*item.DueAt))
+	e.alarm(item)
+	e.line("END:VEVENT")
+}
+
+func (e *encoder) common(item todo.TodoItem, summary string) {
+	e.line("UID:" + UID(item.Id))
+	e.line("DTSTAMP:" + formatTime(item.UpdatedAt))
+	e.line("CREATED:" + formatTime(item.CreatedAt))
+	e.line("LAST-MODIFIED:" + formatTime(item.UpdatedAt))
+	e.line("SUMMARY:" + escape(summary))
+	if item.Description != "" {
+		e.line("DESCRIPTION:" + escape(item.Description))
+	}
+}
+
+// alarm reminds of undone items at their remind time.
+func (e *encoder) alarm(item todo.TodoItem) {
+	if item.RemindAt == nil || item.Done {
+		return
+	}
+	e.line("BEGIN:VALARM")
+	e.line("ACTION:DISPLAY")
+	e.line("DESCRIPTION:" + escape(item.Title))
+	e.line("TRIGGER;VALUE=DATE-TIME:" + formatTime(*item.RemindAt))
+	e.line("END:VALARM")
+}
+
+// line writes a content line, folded into lines of at most maxLineLength
+// octets without splitting characters.
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// continuation lines start with a space
+		limit = maxLineLength - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	_, e.err = io.WriteString(e.w, b.String())
+}
+
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
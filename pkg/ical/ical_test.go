@@ -0,0 +1,86 @@
+package ical
+
+import (
+	"bytes"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() todo.CalendarFeed {
+	created := time.Date(2023, 7, 1, 8, 0, 0, 0, time.UTC)
+	updated := time.Date(2023, 7, 2, 8, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2023, 7, 10, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	remindAt := time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)
+	return todo.CalendarFeed{
+		Name: "Release, v1",
+		Items: []todo.TodoItem{
+			{Id: 1, Title: "Tag; sign", Description: "v1.2.3\nsigned", Priority: todo.PriorityHigh,
+				DueAt: &dueAt, RemindAt: &remindAt, CreatedAt: created, UpdatedAt: updated},
+			{Id: 2, Title: "Branch", Done: true, DueAt: &dueAt, RemindAt: &remindAt, CreatedAt: created, UpdatedAt: updated},
+			{Id: 3, Title: "Someday", CreatedAt: created, UpdatedAt: updated},
+		},
+	}
+}
+
+func TestEncode(t *testing.T) {
+	testTable := []struct {
+		name      string
+		component string
+		want      string
+	}{
+		{
+			name:      "Todo",
+			component: todo.ComponentTodo,
+			want: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//LittleMikle//ToDo_List//EN\r\nCALSCALE:GREGORIAN\r\nX-WR-CALNAME:Release\\, v1\r\n" +
+				"BEGIN:VTODO\r\nUID:item-1@todo-list\r\nDTSTAMP:20230702T080000Z\r\nCREATED:20230701T080000Z\r\nLAST-MODIFIED:20230702T080000Z\r\n" +
+				"SUMMARY:Tag\\; sign\r\nDESCRIPTION:v1.2.3\\nsigned\r\nDUE:20230710T103000Z\r\nSTATUS:NEEDS-ACTION\r\nPRIORITY:1\r\n" +
+				"BEGIN:VALARM\r\nACTION:DISPLAY\r\nDESCRIPTION:Tag\\; sign\r\nTRIGGER;VALUE=DATE-TIME:20230710T090000Z\r\nEND:VALARM\r\nEND:VTODO\r\n" +
+				"BEGIN:VTODO\r\nUID:item-2@todo-list\r\nDTSTAMP:20230702T080000Z\r\nCREATED:20230701T080000Z\r\nLAST-MODIFIED:20230702T080000Z\r\n" +
+				"SUMMARY:Branch\r\nDUE:20230710T103000Z\r\nSTATUS:COMPLETED\r\nCOMPLETED:20230702T080000Z\r\nPERCENT-COMPLETE:100\r\nEND:VTODO\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+		{
+			name:      "Event",
+			component: todo.ComponentEvent,
+			want: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//LittleMikle//ToDo_List//EN\r\nCALSCALE:GREGORIAN\r\nX-WR-CALNAME:Release\\, v1\r\n" +
+				"BEGIN:VEVENT\r\nUID:item-1@todo-list\r\nDTSTAMP:20230702T080000Z\r\nCREATED:20230701T080000Z\r\nLAST-MODIFIED:20230702T080000Z\r\n" +
+				"SUMMARY:Tag\\; sign\r\nDESCRIPTION:v1.2.3\\nsigned\r\nDTSTART:20230710T103000Z\r\nDTEND:20230710T103000Z\r\n" +
+				"BEGIN:VALARM\r\nACTION:DISPLAY\r\nDESCRIPTION:Tag\\; sign\r\nTRIGGER;VALUE=DATE-TIME:20230710T090000Z\r\nEND:VALARM\r\nEND:VEVENT\r\n" +
+				"BEGIN:VEVENT\r\nUID:item-2@todo-list\r\nDTSTAMP:20230702T080000Z\r\nCREATED:20230701T080000Z\r\nLAST-MODIFIED:20230702T080000Z\r\n" +
+				"SUMMARY:✓ Branch\r\nDTSTART:20230710T103000Z\r\nDTEND:20230710T103000Z\r\nEND:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := Encode(&b, testFeed(), testCase.component)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, b.String())
+		})
+	}
+}
+
+func TestEncode_Folding(t *testing.T) {
+	feed := todo.CalendarFeed{Name: strings.Repeat("é", 60)}
+
+	var b bytes.Buffer
+	err := Encode(&b, feed, todo.ComponentTodo)
+	assert.NoError(t, err)
+
+	var unfolded string
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+		if strings.HasPrefix(line, " ") {
+			unfolded += line[1:]
+		} else {
+			unfolded += "\n" + line
+		}
+	}
+	assert.Contains(t, unfolded, "\nX-WR-CALNAME:"+feed.Name+"\n")
+}
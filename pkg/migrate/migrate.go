@@ -0,0 +1,355 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationsTable records the applied migrations. It is not named like the
+// schema_migrations table of golang-migrate, which databases may already have.
+const migrationsTable = "todo_migrations"
+
+// existingTable is a table of the first migration. Finding it in a database
+// without applied migrations means its schema was set up by other means.
+const existingTable = "users"
+
+// lockKey identifies the Postgres advisory lock held while migrating, so that
+// replicas started together don't apply the same migration twice.
+const lockKey = 0x746f646f
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type appliedMigration struct {
+	Version   int       `db:"version"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// Load reads the NNNNNN_name.up.sql and NNNNNN_name.down.sql pairs of fsys,
+// sorted by version. The checksum of a migration covers its up file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q", match[1])
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migration.Name, match[2], version)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %06d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down reverts the last applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.locked(ctx, func(conn *sqlx.Conn, applied map[int]appliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				return m.revert(ctx, conn, m.migrations[i])
+			}
+		}
+
+		log.Info().Msg("no migrations to revert")
+		return nil
+	})
+}
+
+// To applies or reverts migrations until version is the last applied one.
+// Version 0 reverts all of them.
+func (m *Migrator) To(ctx context.Context, version int) error {
+	if version != 0 && m.find(version) < 0 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.locked(ctx, func(conn *sqlx.Conn, applied map[int]appliedMigration) error {
+		if len(applied) == 0 && version != 0 {
+			if err := checkEmpty(ctx, conn); err != nil {
+				return err
+			}
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; ok && migration.Version > version {
+				if err := m.revert(ctx, conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+				if err := m.apply(ctx, conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for databases whose schema was set up before migrations were tracked.
+func (m *Migrator) Baseline(ctx context.Context, version int) error {
+	if m.find(version) < 0 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.locked(ctx, func(conn *sqlx.Conn, applied map[int]appliedMigration) error {
+		if len(applied) > 0 {
+			return errors.New("migrations are already applied, baseline is only for untracked databases")
+		}
+
+		tx, err := conn.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (version, name, checksum) VALUES ($1, $2, $3)", migrationsTable)
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, err = tx.ExecContext(ctx, query, migration.Version, migration.Name, migration.Checksum); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+
+		log.Info().Msgf("marked migrations up to %06d as applied", version)
+		return nil
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+
+	err := m.locked(ctx, func(conn *sqlx.Conn, applied map[int]appliedMigration) error {
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if a, ok := applied[migration.Version]; ok {
+				appliedAt := a.AppliedAt
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// locked runs fn on a connection holding the migration lock, once the applied
+// migrations have been checked against the files they were applied from.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sqlx.Conn, applied map[int]appliedMigration) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			log.Error().Err(err).Msg("failed to release migration lock")
+		}
+	}()
+
+	createQuery := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s
+		(
+			version    bigint primary key,
+			name       varchar(255) not null,
+			checksum   varchar(64)  not null,
+			applied_at timestamptz  not null default now()
+		)`, migrationsTable)
+	if _, err = conn.ExecContext(ctx, createQuery); err != nil {
+		return fmt.Errorf("failed to create %s: %w", migrationsTable, err)
+	}
+
+	var rows []appliedMigration
+	query := fmt.Sprintf("SELECT version, checksum, applied_at FROM %s", migrationsTable)
+	if err = conn.SelectContext(ctx, &rows, query); err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	applied := make(map[int]appliedMigration, len(rows))
+	for _, row := range rows {
+		i := m.find(row.Version)
+		if i < 0 {
+			return fmt.Errorf("applied migration %d has no file", row.Version)
+		}
+		if m.migrations[i].Checksum != row.Checksum {
+			return fmt.Errorf("migration %06d_%s was changed after it was applied",
+				row.Version, m.migrations[i].Name)
+		}
+		applied[row.Version] = row
+	}
+
+	return fn(conn, applied)
+}
+
+// checkEmpty fails for databases that have tables but no applied migrations,
+// which the first migration would fail on.
+func checkEmpty(ctx context.Context, conn *sqlx.Conn) error {
+	var exists bool
+	if err := conn.GetContext(ctx, &exists, "SELECT to_regclass($1) IS NOT NULL", existingTable); err != nil {
+		return fmt.Errorf("failed to check for existing tables: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("table %s exists but no migrations are recorded, "+
+			"record the ones its schema has with: migrate baseline <version>", existingTable)
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, migration.Up); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to apply migration %06d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (version, name, checksum) VALUES ($1, $2, $3)", migrationsTable)
+	if _, err = tx.ExecContext(ctx, query, migration.Version, migration.Name, migration.Checksum); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Info().Msgf("applied migration %06d_%s", migration.Version, migration.Name)
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, migration.Down); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to revert migration %06d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE version = $1", migrationsTable)
+	if _, err = tx.ExecContext(ctx, query, migration.Version); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Info().Msgf("reverted migration %06d_%s", migration.Version, migration.Name)
+	return nil
+}
+
+func (m *Migrator) find(version int) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,248 @@
+package migrate
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"000001_init.up.sql":     {Data: []byte("CREATE TABLE users (id serial);")},
+		"000001_init.down.sql":   {Data: []byte("DROP TABLE users;")},
+		"000002_lists.up.sql":    {Data: []byte("CREATE TABLE lists (id serial);")},
+		"000002_lists.down.sql":  {Data: []byte("DROP TABLE lists;")},
+		"schema.go":              {Data: []byte("package schema")},
+		"000003_broken.down.sql": {Data: []byte("")},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	testTable := []struct {
+		name         string
+		fsys         fstest.MapFS
+		wantVersions []int
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			fsys: func() fstest.MapFS {
+				fsys := testFS()
+				delete(fsys, "000003_broken.down.sql")
+				return fsys
+			}(),
+			wantVersions: []int{1, 2},
+		},
+		{
+			name:    "Missing Up File",
+			fsys:    testFS(),
+			wantErr: true,
+		},
+		{
+			name: "Shared Version",
+			fsys: fstest.MapFS{
+				"000001_init.up.sql":    {Data: []byte("SELECT 1;")},
+				"000001_init.down.sql":  {Data: []byte("SELECT 1;")},
+				"000001_other.up.sql":   {Data: []byte("SELECT 1;")},
+				"000001_other.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Load(testCase.fsys)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			versions := make([]int, 0, len(got))
+			for _, migration := range got {
+				assert.Len(t, migration.Checksum, 64)
+				versions = append(versions, migration.Version)
+			}
+			assert.Equal(t, testCase.wantVersions, versions)
+		})
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	fsys := testFS()
+	delete(fsys, "000003_broken.down.sql")
+	m, err := NewMigrator(db, fsys)
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("SELECT pg_advisory_lock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS todo_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT version, checksum, applied_at FROM todo_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).
+						AddRow(1, m.migrations[0].Checksum, time.Now()))
+
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE TABLE lists").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO todo_migrations").
+					WithArgs(2, "lists", m.migrations[1].Checksum).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				mock.ExpectExec("SELECT pg_advisory_unlock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "Changed Migration",
+			mock: func() {
+				mock.ExpectExec("SELECT pg_advisory_lock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS todo_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT version, checksum, applied_at FROM todo_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).
+						AddRow(1, "other checksum", time.Now()))
+
+				mock.ExpectExec("SELECT pg_advisory_unlock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Untracked Database",
+			mock: func() {
+				mock.ExpectExec("SELECT pg_advisory_lock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS todo_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT version, checksum, applied_at FROM todo_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}))
+				mock.ExpectQuery("SELECT to_regclass(.+)").WithArgs("users").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				mock.ExpectExec("SELECT pg_advisory_unlock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := m.Up(context.Background())
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	fsys := testFS()
+	delete(fsys, "000003_broken.down.sql")
+	m, err := NewMigrator(db, fsys)
+	assert.NoError(t, err)
+
+	mock.ExpectExec("SELECT pg_advisory_lock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS todo_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM todo_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).
+			AddRow(1, m.migrations[0].Checksum, time.Now()).
+			AddRow(2, m.migrations[1].Checksum, time.Now()))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE lists").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM todo_migrations").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectExec("SELECT pg_advisory_unlock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, m.Down(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Baseline(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	fsys := testFS()
+	delete(fsys, "000003_broken.down.sql")
+	m, err := NewMigrator(db, fsys)
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("SELECT pg_advisory_lock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS todo_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT version, checksum, applied_at FROM todo_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}))
+
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO todo_migrations").
+					WithArgs(1, "init", m.migrations[0].Checksum).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO todo_migrations").
+					WithArgs(2, "lists", m.migrations[1].Checksum).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				mock.ExpectExec("SELECT pg_advisory_unlock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "Already Tracked",
+			mock: func() {
+				mock.ExpectExec("SELECT pg_advisory_lock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS todo_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT version, checksum, applied_at FROM todo_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).
+						AddRow(1, m.migrations[0].Checksum, time.Now()))
+
+				mock.ExpectExec("SELECT pg_advisory_unlock(.+)").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := m.Baseline(context.Background(), 2)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
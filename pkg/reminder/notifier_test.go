@@ -0,0 +1,39 @@
+package reminder
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSMTPNotifier_Notify_Canceled(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// the server accepts the connection but never greets
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	n := NewSMTPNotifier(SMTPConfig{Host: host, Port: port, From: "todo@example.com"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	err = n.Notify(ctx, todo.Reminder{Username: "user@example.com", Title: "Tag"})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
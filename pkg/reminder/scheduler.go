@@ -0,0 +1,107 @@
+package reminder
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, reminder todo.Reminder) error
+}
+
+type Config struct {
+	Interval    time.Duration
+	CatchUp     time.Duration
+	Lease       time.Duration
+	BatchSize   int
+	MaxAttempts int
+}
+
+// Scheduler periodically turns items coming due into pending deliveries and
+// sends them through a Notifier. Several replicas may run it at once: each
+// delivery is leased by a single replica, see repository.Reminder.Claim.
+type Scheduler struct {
+	repo     repository.Reminder
+	notifier Notifier
+	cfg      Config
+}
+
+func NewScheduler(repo repository.Reminder, notifier Notifier, cfg Config) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		notifier: notifier,
+		cfg:      cfg,
+	}
+}
+
+// Run scans for reminders every interval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	enqueued, err := s.repo.Enqueue(ctx, s.cfg.CatchUp)
+	if err != nil {
+		log.Error().Err(err).Msg("failed with reminders enqueue")
+		return
+	}
+	if enqueued > 0 {
+		log.Info().Msgf("enqueued %d reminders", enqueued)
+	}
+
+	for ctx.Err() == nil {
+		reminders, err := s.repo.Claim(ctx, s.cfg.BatchSize, s.cfg.MaxAttempts, s.cfg.Lease)
+		if err != nil {
+			log.Error().Err(err).Msg("failed with reminders claim")
+			return
+		}
+
+		for _, reminder := range reminders {
+			s.deliver(ctx, reminder)
+		}
+
+		if len(reminders) < s.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+func (s *Scheduler) deliver(ctx context.Context, reminder todo.Reminder) {
+	err := s.notifier.Notify(ctx, reminder)
+	if err == nil {
+		if err = s.repo.MarkSent(ctx, reminder.Id); err != nil {
+			log.Error().Err(err).Msgf("failed to mark reminder %d as sent", reminder.Id)
+		}
+		return
+	}
+
+	log.Warn().Err(err).Msgf("failed to deliver reminder %d, attempt %d", reminder.Id, reminder.Attempts)
+	if err = s.repo.MarkFailed(ctx, reminder.Id, err.Error(), s.backoff(reminder.Attempts)); err != nil {
+		log.Error().Err(err).Msgf("failed to mark reminder %d as failed", reminder.Id)
+	}
+}
+
+// backoff doubles the retry delay with every attempt, starting at the interval.
+func (s *Scheduler) backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 10 {
+		attempts = 10
+	}
+	return s.cfg.Interval << (attempts - 1)
+}
@@ -0,0 +1,70 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeRepo struct {
+	batches [][]todo.Reminder
+	sent    []int
+	failed  map[int]time.Duration
+}
+
+func (r *fakeRepo) Enqueue(ctx context.Context, catchUp time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeRepo) Claim(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]todo.Reminder, error) {
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch, nil
+}
+
+func (r *fakeRepo) MarkSent(ctx context.Context, id int) error {
+	r.sent = append(r.sent, id)
+	return nil
+}
+
+func (r *fakeRepo) MarkFailed(ctx context.Context, id int, reason string, retryIn time.Duration) error {
+	r.failed[id] = retryIn
+	return nil
+}
+
+type fakeNotifier struct {
+	failing map[int]bool
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, reminder todo.Reminder) error {
+	if n.failing[reminder.ItemId] {
+		return errors.New("unreachable")
+	}
+	return nil
+}
+
+func TestScheduler_tick(t *testing.T) {
+	repo := &fakeRepo{
+		batches: [][]todo.Reminder{
+			{{Id: 1, ItemId: 10, Attempts: 1}, {Id: 2, ItemId: 20, Attempts: 3}},
+			{{Id: 3, ItemId: 30, Attempts: 1}},
+		},
+		failed: map[int]time.Duration{},
+	}
+	notifier := &fakeNotifier{failing: map[int]bool{20: true}}
+
+	s := NewScheduler(repo, notifier, Config{Interval: time.Minute, BatchSize: 2, MaxAttempts: 5})
+
+	s.tick(context.Background())
+
+	assert.Equal(t, []int{1, 3}, repo.sent)
+	assert.Len(t, repo.failed, 1)
+	assert.Equal(t, 4*time.Minute, repo.failed[2])
+	assert.Empty(t, repo.batches)
+}
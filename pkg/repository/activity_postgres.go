@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+	"strings"
+	"time"
+)
+
+type ActivityPostgres struct {
+	db *sqlx.DB
+}
+
+func NewActivityPostgres(db *sqlx.DB) *ActivityPostgres {
+	return &ActivityPostgres{db: db}
+}
+
+// GetAll returns the activity of a list, newest first. Access to the list is
+// checked by the caller.
+func (r *ActivityPostgres) GetAll(ctx context.Context, listId int, query todo.PageQuery) ([]todo.Activity, todo.PageInfo, error) {
+	var page todo.PageInfo
+	conditions := "a.list_id = $1"
+	args := []interface{}{listId}
+
+	countQuery := fmt.Sprintf("SELECT count(*) FROM %s a WHERE %s", activityTable, conditions)
+	if err := r.db.GetContext(ctx, &page.Total, countQuery, args...); err != nil {
+		return nil, page, fmt.Errorf("failed with GetAll activity count: %w", err)
+	}
+
+	if query.Cursor != "" {
+		c, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, page, err
+		}
+		if c.Sort != "id" {
+			return nil, page, fmt.Errorf("%w: cursor does not match sort", todo.ErrValidation)
+		}
+		conditions += " AND a.id < $2"
+		args = append(args, c.Id)
+	}
+
+	var activities []todo.Activity
+	selectQuery := fmt.Sprintf(`SELECT a.id, a.list_id, a.item_id, a.user_id, u.username, a.action, a.changes, a.created_at
+									FROM %s a LEFT JOIN %s u on u.id = a.user_id WHERE %s ORDER BY a.id DESC LIMIT $%d`,
+		activityTable, usersTable, conditions, len(args)+1)
+	args = append(args, query.Limit+1)
+
+	if err := r.db.SelectContext(ctx, &activities, selectQuery, args...); err != nil {
+		return nil, page, fmt.Errorf("failed with GetAll activity: %w", err)
+	}
+
+	if len(activities) > query.Limit {
+		activities = activities[:query.Limit]
+		page.NextCursor = encodeCursor("id", activities[len(activities)-1].Id, "")
+	}
+	return activities, page, nil
+}
+
+// logActivity appends an entry to the activity of a list. It runs in the
+// transaction of the change it records.
+func logActivity(ctx context.Context, tx *sqlx.Tx, activity todo.Activity) error {
+	query := fmt.Sprintf("INSERT INTO %s (list_id, item_id, user_id, action, changes) VALUES ($1, $2, $3, $4, $5)",
+		activityTable)
+	_, err := tx.ExecContext(ctx, query, activity.ListId, activity.ItemId, activity.UserId, activity.Action, activity.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to log activity: %w", err)
+	}
+	return nil
+}
+
+// logActivities appends several entries with one statement.
+func logActivities(ctx context.Context, tx *sqlx.Tx, activities []todo.Activity) error {
+	values := make([]string, 0, len(activities))
+	args := make([]interface{}, 0, len(activities)*5)
+	for _, activity := range activities {
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5))
+		args = append(args, activity.ListId, activity.ItemId, activity.UserId, activity.Action, activity.Changes)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (list_id, item_id, user_id, action, changes) VALUES %s",
+		activityTable, strings.Join(values, ", "))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to log activity: %w", err)
+	}
+	return nil
+}
+
+// addChange records a field in changes if its value differs.
+func addChange(changes todo.Changes, field string, from, to interface{}) {
+	fromTime, fromIsTime := from.(time.Time)
+	toTime, toIsTime := to.(time.Time)
+	if fromIsTime && toIsTime && fromTime.Equal(toTime) || !fromIsTime && !toIsTime && from == to {
+		return
+	}
+	changes[field] = todo.Change{From: from, To: to}
+}
+
+// timeValue turns an optional time into a value for addChange.
+func timeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestActivityPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewActivityPostgres(db)
+
+	createdAt := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "list_id", "item_id", "user_id", "username", "action", "changes", "created_at"}
+
+	mock.ExpectQuery("SELECT count(.+) FROM activity a WHERE a.list_id = (.+)").
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("SELECT (.+) FROM activity a LEFT JOIN users u on (.+) WHERE a.list_id = (.+) ORDER BY a.id DESC LIMIT (.+)").
+		WithArgs(1, 3).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(3, 1, 2, 1, "user", todo.ActionItemDone, []byte(`{"done":{"from":false,"to":true}}`), createdAt).
+		AddRow(2, 1, 2, nil, nil, todo.ActionItemCreated, []byte(`{}`), createdAt).
+		AddRow(1, 1, nil, 1, "user", todo.ActionListCreated, []byte(`{}`), createdAt))
+
+	got, page, err := r.GetAll(context.Background(), 1, todo.PageQuery{Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, 3, page.Total)
+	assert.NotEmpty(t, page.NextCursor)
+	assert.Equal(t, todo.Changes{"done": {From: false, To: true}}, got[0].Changes)
+	assert.Equal(t, "user", *got[0].Username)
+	assert.Nil(t, got[1].UserId)
+
+	mock.ExpectQuery("SELECT count(.+) FROM activity a WHERE a.list_id = (.+)").
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("SELECT (.+) WHERE a.list_id = (.+) AND a.id < (.+) ORDER BY a.id DESC LIMIT (.+)").
+		WithArgs(1, 2, 3).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(1, 1, nil, 1, "user", todo.ActionListCreated, []byte(`{}`), createdAt))
+
+	got, page, err = r.GetAll(context.Background(), 1, todo.PageQuery{Limit: 2, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Empty(t, page.NextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddChange(t *testing.T) {
+	at := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	changes := todo.Changes{}
+	addChange(changes, "title", "title", "title")
+	addChange(changes, "due_at", at, at.In(time.FixedZone("UTC+3", 3*60*60)))
+	addChange(changes, "remind_at", nil, nil)
+	addChange(changes, "done", false, true)
+	addChange(changes, "priority", nil, 0)
+
+	assert.Equal(t, todo.Changes{
+		"done":     {From: false, To: true},
+		"priority": {From: nil, To: 0},
+	}, changes)
+}
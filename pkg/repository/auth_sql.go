@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/jmoiron/sqlx"
@@ -16,24 +17,47 @@ func NewAuthPostgres(db *sqlx.DB) *AuthPostgres {
 	}
 }
 
-func (r *AuthPostgres) CreateUser(user todo.User) (int, error) {
+func (r *AuthPostgres) CreateUser(ctx context.Context, user todo.User) (int, error) {
 	var id int
 	query := fmt.Sprintf("INSERT INTO %s (name, username, password_hash) values ($1, $2, $3) RETURNING id", usersTable)
 
-	row := r.db.QueryRow(query, user.Name, user.Username, user.Password)
+	row := r.db.QueryRowContext(ctx, query, user.Name, user.Username, user.Password)
 	err := row.Scan(&id)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%w: username is already taken", todo.ErrConflict)
+		}
 		return 0, err
 	}
 	return id, nil
 }
 
-func (r *AuthPostgres) GetUser(username, password string) (todo.User, error) {
+func (r *AuthPostgres) GetUser(ctx context.Context, username string) (todo.User, error) {
 	var user todo.User
-	query := fmt.Sprintf("SELECT id FROM %s WHERE username=$1 AND password_hash=$2", usersTable)
-	err := r.db.Get(&user, query, username, password)
+	query := fmt.Sprintf("SELECT id, name, username, password_hash FROM %s WHERE username=$1", usersTable)
+	err := r.db.GetContext(ctx, &user, query, username)
 	if err != nil {
-		return user, fmt.Errorf("failed to GetUser: %w", err)
+		return user, notFound(fmt.Errorf("failed to GetUser: %w", err), "user")
 	}
 	return user, nil
 }
+
+func (r *AuthPostgres) UpdatePasswordHash(ctx context.Context, userId int, passwordHash string) error {
+	query := fmt.Sprintf("UPDATE %s SET password_hash=$1, updated_at=now() WHERE id=$2", usersTable)
+	_, err := r.db.ExecContext(ctx, query, passwordHash, userId)
+	if err != nil {
+		return fmt.Errorf("failed to UpdatePasswordHash: %w", err)
+	}
+	return nil
+}
+
+// HasLegacyPasswordHashes reports whether any user still has a pre-bcrypt
+// SHA3 password hash, which can't be verified without the legacy salt.
+func (r *AuthPostgres) HasLegacyPasswordHashes(ctx context.Context) (bool, error) {
+	var exists bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE password_hash NOT LIKE '$2%%')", usersTable)
+	if err := r.db.GetContext(ctx, &exists, query); err != nil {
+		return false, fmt.Errorf("failed to HasLegacyPasswordHashes: %w", err)
+	}
+	return exists, nil
+}
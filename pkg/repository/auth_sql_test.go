@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
@@ -18,11 +21,12 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 	r := NewAuthPostgres(db)
 
 	testTable := []struct {
-		name    string
-		mock    func()
-		input   todo.User
-		want    int
-		wantErr bool
+		name      string
+		mock      func()
+		input     todo.User
+		want      int
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "OK",
@@ -52,15 +56,32 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Username Taken",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO users").
+					WithArgs("Test", "test", "password").WillReturnError(&pq.Error{Code: uniqueViolation})
+			},
+			input: todo.User{
+				Name:     "Test",
+				Username: "test",
+				Password: "password",
+			},
+			wantErr:   true,
+			wantErrIs: todo.ErrConflict,
+		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.CreateUser(testCase.input)
+			got, err := r.CreateUser(context.Background(), testCase.input)
 			if testCase.wantErr {
 				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, testCase.want, got)
@@ -79,27 +100,22 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 
 	r := NewAuthPostgres(db)
 
-	type args struct {
-		username string
-		password string
-	}
-
 	testTable := []struct {
 		name    string
 		mock    func()
-		input   args
+		input   string
 		want    todo.User
 		wantErr bool
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "username", "password"}).
+				rows := sqlmock.NewRows([]string{"id", "name", "username", "password_hash"}).
 					AddRow(1, "Test", "test", "password")
 				mock.ExpectQuery("SELECT (.+) FROM users").
-					WithArgs("test", "password").WillReturnRows(rows)
+					WithArgs("test").WillReturnRows(rows)
 			},
-			input: args{"test", "password"},
+			input: "test",
 			want: todo.User{
 				Id:       1,
 				Name:     "Test",
@@ -110,11 +126,11 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		{
 			name: "Not Found",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "username", "password"})
+				rows := sqlmock.NewRows([]string{"id", "name", "username", "password_hash"})
 				mock.ExpectQuery("SELECT (.+) FROM users").
-					WithArgs("not", "found").WillReturnRows(rows)
+					WithArgs("notfound").WillReturnRows(rows)
 			},
-			input:   args{"not", "found"},
+			input:   "notfound",
 			wantErr: true,
 		},
 	}
@@ -123,7 +139,7 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.GetUser(testCase.input.username, testCase.input.password)
+			got, err := r.GetUser(context.Background(), testCase.input)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -134,3 +150,80 @@ func TestAuthPostgres_GetUser(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthPostgres_UpdatePasswordHash(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub conn to db")
+	}
+	defer db.Close()
+
+	r := NewAuthPostgres(db)
+
+	type args struct {
+		userId       int
+		passwordHash string
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   args
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE users SET password_hash=\\$1, updated_at=now\\(\\) WHERE id=\\$2").
+					WithArgs("$2a$10$hash", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{1, "$2a$10$hash"},
+		},
+		{
+			name: "Update Error",
+			mock: func() {
+				mock.ExpectExec("UPDATE users SET password_hash").
+					WithArgs("$2a$10$hash", 1).WillReturnError(errors.New("update error"))
+			},
+			input:   args{1, "$2a$10$hash"},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.UpdatePasswordHash(context.Background(), testCase.input.userId, testCase.input.passwordHash)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_HasLegacyPasswordHashes(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub conn to db")
+	}
+	defer db.Close()
+
+	r := NewAuthPostgres(db)
+
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM users WHERE password_hash NOT LIKE (.+)\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	legacy, err := r.HasLegacyPasswordHashes(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, legacy)
+
+	mock.ExpectQuery("SELECT EXISTS (.+)").WillReturnError(errors.New("select error"))
+
+	_, err = r.HasLegacyPasswordHashes(context.Background())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
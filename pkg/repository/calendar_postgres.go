@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+type CalendarPostgres struct {
+	db *sqlx.DB
+}
+
+func NewCalendarPostgres(db *sqlx.DB) *CalendarPostgres {
+	return &CalendarPostgres{db: db}
+}
+
+// SetToken stores the hash of the feed token of the user, replacing the
+// previous one.
+func (r *CalendarPostgres) SetToken(ctx context.Context, userId int, tokenHash string) error {
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, token_hash) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()`, calendarFeedsTable)
+	if _, err := r.db.ExecContext(ctx, query, userId, tokenHash); err != nil {
+		return fmt.Errorf("failed to set calendar token: %w", err)
+	}
+	return nil
+}
+
+func (r *CalendarPostgres) DeleteToken(ctx context.Context, userId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", calendarFeedsTable)
+	res, err := r.db.ExecContext(ctx, query, userId)
+	if err != nil {
+		return err
+	}
+	return affected(res, "calendar token")
+}
+
+func (r *CalendarPostgres) GetUserId(ctx context.Context, tokenHash string) (int, error) {
+	var userId int
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE token_hash = $1", calendarFeedsTable)
+	if err := r.db.GetContext(ctx, &userId, query, tokenHash); err != nil {
+		return 0, notFound(err, "calendar feed")
+	}
+	return userId, nil
+}
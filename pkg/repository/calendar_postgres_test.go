@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestCalendarPostgres_SetToken(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewCalendarPostgres(db)
+
+	mock.ExpectExec("INSERT INTO calendar_feeds \\(user_id, token_hash\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT \\(user_id\\) DO UPDATE (.+)").
+		WithArgs(1, "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, r.SetToken(context.Background(), 1, "hash"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalendarPostgres_DeleteToken(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewCalendarPostgres(db)
+
+	mock.ExpectExec("DELETE FROM calendar_feeds WHERE user_id = \\$1").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.DeleteToken(context.Background(), 1))
+
+	mock.ExpectExec("DELETE FROM calendar_feeds (.+)").
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, r.DeleteToken(context.Background(), 2), todo.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalendarPostgres_GetUserId(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewCalendarPostgres(db)
+
+	mock.ExpectQuery("SELECT user_id FROM calendar_feeds WHERE token_hash = \\$1").
+		WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	userId, err := r.GetUserId(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userId)
+
+	mock.ExpectQuery("SELECT user_id FROM calendar_feeds (.+)").
+		WithArgs("other").WillReturnError(sql.ErrNoRows)
+
+	_, err = r.GetUserId(context.Background(), "other")
+	assert.ErrorIs(t, err, todo.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
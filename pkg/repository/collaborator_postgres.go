@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+)
+
+type CollaboratorPostgres struct {
+	db *sqlx.DB
+}
+
+func NewCollaboratorPostgres(db *sqlx.DB) *CollaboratorPostgres {
+	return &CollaboratorPostgres{db: db}
+}
+
+// Share gives the user with the given username a role on the list, or
+// changes the role they already have. Only the owner of the list may share it.
+// The role change is logged as activity of the list.
+func (r *CollaboratorPostgres) Share(ctx context.Context, userId, listId int, username, role string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var ownerLinks int
+	ownerQuery := fmt.Sprintf("SELECT count(*) FROM %s ul WHERE ul.user_id = $1 AND ul.list_id = $2 AND %s",
+		usersListsTable, ownerAccess)
+	if err = tx.GetContext(ctx, &ownerLinks, ownerQuery, userId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if ownerLinks == 0 {
+		tx.Rollback()
+		return fmt.Errorf("%w: only the list owner can share it", todo.ErrForbidden)
+	}
+
+	var collaboratorId int
+	userQuery := fmt.Sprintf("SELECT id FROM %s WHERE username = $1", usersTable)
+	if err = tx.GetContext(ctx, &collaboratorId, userQuery, username); err != nil {
+		tx.Rollback()
+		return notFound(fmt.Errorf("failed to find user %q: %w", username, err), "user")
+	}
+	if collaboratorId == userId {
+		tx.Rollback()
+		return fmt.Errorf("%w: owner can not change own role", todo.ErrConflict)
+	}
+
+	var oldRole sql.NullString
+	shareQuery := fmt.Sprintf(`WITH old AS (SELECT role FROM %s WHERE user_id = $1 AND list_id = $2)
+									INSERT INTO %s (user_id, list_id, role) VALUES ($1, $2, $3)
+									ON CONFLICT (user_id, list_id) DO UPDATE SET role = EXCLUDED.role, updated_at = now()
+									RETURNING (SELECT role FROM old)`,
+		usersListsTable, usersListsTable)
+	if err = tx.GetContext(ctx, &oldRole, shareQuery, collaboratorId, listId, role); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	changes := todo.Changes{}
+	var from interface{}
+	if oldRole.Valid {
+		from = oldRole.String
+	}
+	addChange(changes, username, from, role)
+	if len(changes) > 0 {
+		err = logActivity(ctx, tx, todo.Activity{ListId: listId, UserId: &userId, Action: todo.ActionListShared, Changes: changes})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *CollaboratorPostgres) GetAll(ctx context.Context, userId, listId int) ([]todo.Collaborator, error) {
+	var collaborators []todo.Collaborator
+	query := fmt.Sprintf(`SELECT u.id AS user_id, u.name, u.username, c.role, c.created_at, c.updated_at FROM %s c INNER JOIN %s u on u.id = c.user_id
+									INNER JOIN %s ul on ul.list_id = c.list_id WHERE c.list_id = $1 AND ul.user_id = $2 ORDER BY u.id`,
+		usersListsTable, usersTable, usersListsTable)
+	if err := r.db.SelectContext(ctx, &collaborators, query, listId, userId); err != nil {
+		return nil, fmt.Errorf("failed with GetAll collaborators: %w", err)
+	}
+
+	return collaborators, nil
+}
+
+// Remove revokes access of a collaborator. The owner can remove anyone but
+// themselves, other collaborators can only remove themselves to leave a list.
+// The removal is logged as activity of the list.
+func (r *CollaboratorPostgres) Remove(ctx context.Context, userId, listId, collaboratorId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var removed struct {
+		Username string `db:"username"`
+		Role     string `db:"role"`
+	}
+	query := fmt.Sprintf(`DELETE FROM %s c USING %s ul, %s u
+									WHERE c.list_id = ul.list_id AND c.list_id = $1 AND c.user_id = $2 AND ul.user_id = $3
+									AND u.id = c.user_id AND c.role <> '%s' AND (%s OR ul.user_id = c.user_id)
+									RETURNING u.username, c.role`,
+		usersListsTable, usersListsTable, usersTable, todo.RoleOwner, ownerAccess)
+	if err = tx.GetContext(ctx, &removed, query, listId, collaboratorId, userId); err != nil {
+		tx.Rollback()
+		return notFound(err, "collaborator")
+	}
+
+	changes := todo.Changes{removed.Username: todo.Change{From: removed.Role}}
+	err = logActivity(ctx, tx, todo.Activity{ListId: listId, UserId: &userId, Action: todo.ActionListUnshared, Changes: changes})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestCollaboratorPostgres_Share(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewCollaboratorPostgres(db)
+
+	type args struct {
+		userId   int
+		listId   int
+		username string
+		role     string
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   args
+		wantErr bool
+	}{
+		{
+			name: "OK New Collaborator",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT count(.+) FROM users_lists ul WHERE (.+)").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				mock.ExpectQuery("SELECT id FROM users WHERE (.+)").
+					WithArgs("friend").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+				mock.ExpectQuery("WITH old AS (.+) INSERT INTO users_lists (.+) ON CONFLICT (.+) DO UPDATE (.+) RETURNING").
+					WithArgs(2, 1, "editor").WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(nil))
+
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(1, nil, 1, todo.ActionListShared, `{"friend":{"from":null,"to":"editor"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+			},
+			input: args{1, 1, "friend", "editor"},
+		},
+		{
+			name: "OK Change Role",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT count(.+) FROM users_lists ul WHERE (.+)").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				mock.ExpectQuery("SELECT id FROM users WHERE (.+)").
+					WithArgs("friend").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+				mock.ExpectQuery("WITH old AS (.+) INSERT INTO users_lists (.+) ON CONFLICT (.+) DO UPDATE (.+) RETURNING").
+					WithArgs(2, 1, "viewer").WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("editor"))
+
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(1, nil, 1, todo.ActionListShared, `{"friend":{"from":"editor","to":"viewer"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+			},
+			input: args{1, 1, "friend", "viewer"},
+		},
+		{
+			name: "OK Same Role",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT count(.+) FROM users_lists ul WHERE (.+)").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				mock.ExpectQuery("SELECT id FROM users WHERE (.+)").
+					WithArgs("friend").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+				mock.ExpectQuery("WITH old AS (.+) INSERT INTO users_lists (.+) RETURNING").
+					WithArgs(2, 1, "viewer").WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
+
+				mock.ExpectCommit()
+			},
+			input: args{1, 1, "friend", "viewer"},
+		},
+		{
+			name: "Not Owner",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT count(.+) FROM users_lists ul WHERE (.+)").
+					WithArgs(3, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+				mock.ExpectRollback()
+			},
+			input:   args{3, 1, "friend", "editor"},
+			wantErr: true,
+		},
+		{
+			name: "Unknown User",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT count(.+) FROM users_lists ul WHERE (.+)").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				mock.ExpectQuery("SELECT id FROM users WHERE (.+)").
+					WithArgs("nobody").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			input:   args{1, 1, "nobody", "editor"},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Share(context.Background(), testCase.input.userId, testCase.input.listId, testCase.input.username, testCase.input.role)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCollaboratorPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewCollaboratorPostgres(db)
+
+	rows := sqlmock.NewRows([]string{"user_id", "name", "username", "role"}).
+		AddRow(1, "Owner", "owner", "owner").
+		AddRow(2, "Friend", "friend", "viewer")
+	mock.ExpectQuery("SELECT (.+) FROM users_lists c INNER JOIN users u on (.+)").
+		WithArgs(1, 2).WillReturnRows(rows)
+
+	got, err := r.GetAll(context.Background(), 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.Collaborator{
+		{UserId: 1, Name: "Owner", Username: "owner", Role: "owner"},
+		{UserId: 2, Name: "Friend", Username: "friend", Role: "viewer"},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCollaboratorPostgres_Remove(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewCollaboratorPostgres(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("DELETE FROM users_lists c USING users_lists ul, users u WHERE (.+) RETURNING u.username, c.role").
+		WithArgs(1, 2, 1).WillReturnRows(sqlmock.NewRows([]string{"username", "role"}).AddRow("friend", "editor"))
+	mock.ExpectExec("INSERT INTO activity").
+		WithArgs(1, nil, 1, todo.ActionListUnshared, `{"friend":{"from":"editor","to":null}}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.Remove(context.Background(), 1, 1, 2))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("DELETE FROM users_lists c (.+)").
+		WithArgs(1, 3, 2).WillReturnRows(sqlmock.NewRows([]string{"username", "role"}))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.Remove(context.Background(), 2, 1, 3), todo.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
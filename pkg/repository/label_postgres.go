@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+const labelColumns = "lb.id, lb.name, lb.color, lb.created_at, lb.updated_at"
+
+type LabelPostgres struct {
+	db *sqlx.DB
+}
+
+func NewLabelPostgres(db *sqlx.DB) *LabelPostgres {
+	return &LabelPostgres{db: db}
+}
+
+func (r *LabelPostgres) Create(ctx context.Context, userId int, input todo.LabelInput) (int, error) {
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, color) VALUES ($1, $2, $3) RETURNING id", labelsTable)
+	row := r.db.QueryRowContext(ctx, query, userId, input.Name, input.Color)
+	if err := row.Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%w: label %s already exists", todo.ErrConflict, input.Name)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *LabelPostgres) GetAll(ctx context.Context, userId int) ([]todo.Label, error) {
+	var labels []todo.Label
+	query := fmt.Sprintf("SELECT %s FROM %s lb WHERE lb.user_id = $1 ORDER BY lb.name", labelColumns, labelsTable)
+	if err := r.db.SelectContext(ctx, &labels, query, userId); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (r *LabelPostgres) GetById(ctx context.Context, userId, labelId int) (todo.Label, error) {
+	var label todo.Label
+	query := fmt.Sprintf("SELECT %s FROM %s lb WHERE lb.user_id = $1 AND lb.id = $2", labelColumns, labelsTable)
+	if err := r.db.GetContext(ctx, &label, query, userId, labelId); err != nil {
+		return label, notFound(err, "label")
+	}
+	return label, nil
+}
+
+func (r *LabelPostgres) Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Name != nil {
+		setValues = append(setValues, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *input.Name)
+		argId++
+	}
+
+	if input.Color != nil {
+		setValues = append(setValues, fmt.Sprintf("color=$%d", argId))
+		args = append(args, *input.Color)
+		argId++
+	}
+
+	setValues = append(setValues, "updated_at=now()")
+	setQuery := strings.Join(setValues, ", ")
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE user_id=$%d AND id=$%d", labelsTable, setQuery, argId, argId+1)
+	args = append(args, userId, labelId)
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: label %s already exists", todo.ErrConflict, *input.Name)
+		}
+		return err
+	}
+	return affected(res, "label")
+}
+
+func (r *LabelPostgres) Delete(ctx context.Context, userId, labelId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 AND id = $2", labelsTable)
+	res, err := r.db.ExecContext(ctx, query, userId, labelId)
+	if err != nil {
+		return err
+	}
+	return affected(res, "label")
+}
+
+// GetByItem returns the labels of the user attached to the item.
+func (r *LabelPostgres) GetByItem(ctx context.Context, userId, itemId int) ([]todo.Label, error) {
+	var labels []todo.Label
+	query := fmt.Sprintf(`SELECT %s FROM %s lb INNER JOIN %s il on il.label_id = lb.id
+									WHERE lb.user_id = $1 AND il.item_id = $2 ORDER BY lb.name`,
+		labelColumns, labelsTable, itemsLabelsTable)
+	if err := r.db.SelectContext(ctx, &labels, query, userId, itemId); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// Attach attaches the label of the user to the item, unless it already is.
+// Access to the item is up to the caller.
+func (r *LabelPostgres) Attach(ctx context.Context, userId, itemId, labelId int) error {
+	query := fmt.Sprintf(`INSERT INTO %s (item_id, label_id) SELECT $1, lb.id FROM %s lb
+									WHERE lb.user_id = $2 AND lb.id = $3 ON CONFLICT DO NOTHING`,
+		itemsLabelsTable, labelsTable)
+	_, err := r.db.ExecContext(ctx, query, itemId, userId, labelId)
+	return err
+}
+
+// Detach removes the label of the user from the item, if it is attached.
+func (r *LabelPostgres) Detach(ctx context.Context, userId, itemId, labelId int) error {
+	query := fmt.Sprintf(`DELETE FROM %s il USING %s lb
+									WHERE il.label_id = lb.id AND lb.user_id = $1 AND il.item_id = $2 AND il.label_id = $3`,
+		itemsLabelsTable, labelsTable)
+	_, err := r.db.ExecContext(ctx, query, userId, itemId, labelId)
+	return err
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestLabelPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewLabelPostgres(db)
+	input := todo.LabelInput{Name: "home", Color: "#00ff00"}
+
+	mock.ExpectQuery("INSERT INTO labels").
+		WithArgs(1, "home", "#00ff00").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	id, err := r.Create(context.Background(), 1, input)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, id)
+
+	mock.ExpectQuery("INSERT INTO labels").
+		WithArgs(1, "home", "#00ff00").WillReturnError(&pq.Error{Code: uniqueViolation})
+
+	_, err = r.Create(context.Background(), 1, input)
+	assert.ErrorIs(t, err, todo.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLabelPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewLabelPostgres(db)
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		input     todo.UpdateLabelInput
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE labels SET name=\\$1, color=\\$2, updated_at=now\\(\\) WHERE user_id=\\$3 AND id=\\$4").
+					WithArgs("work", "#0000ff", 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: todo.UpdateLabelInput{Name: stringPointer("work"), Color: stringPointer("#0000ff")},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE labels SET color=\\$1, updated_at=now\\(\\) WHERE (.+)").
+					WithArgs("#0000ff", 1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			input:     todo.UpdateLabelInput{Color: stringPointer("#0000ff")},
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+		{
+			name: "Name Taken",
+			mock: func() {
+				mock.ExpectExec("UPDATE labels SET name=\\$1, updated_at=now\\(\\) WHERE (.+)").
+					WithArgs("work", 1, 2).WillReturnError(&pq.Error{Code: uniqueViolation})
+			},
+			input:     todo.UpdateLabelInput{Name: stringPointer("work")},
+			wantErr:   true,
+			wantErrIs: todo.ErrConflict,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Update(context.Background(), 1, 2, testCase.input)
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, testCase.wantErrIs)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLabelPostgres_GetById(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewLabelPostgres(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM labels lb WHERE lb.user_id = \\$1 AND lb.id = \\$2").
+		WithArgs(1, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color"}).AddRow(2, "home", "#00ff00"))
+
+	label, err := r.GetById(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, todo.Label{Id: 2, Name: "home", Color: "#00ff00"}, label)
+
+	mock.ExpectQuery("SELECT (.+) FROM labels lb (.+)").
+		WithArgs(1, 3).WillReturnError(sql.ErrNoRows)
+
+	_, err = r.GetById(context.Background(), 1, 3)
+	assert.ErrorIs(t, err, todo.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLabelPostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewLabelPostgres(db)
+
+	mock.ExpectExec("DELETE FROM labels WHERE user_id = \\$1 AND id = \\$2").
+		WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.Delete(context.Background(), 1, 2))
+
+	mock.ExpectExec("DELETE FROM labels (.+)").
+		WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, r.Delete(context.Background(), 1, 3), todo.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLabelPostgres_AttachDetach(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewLabelPostgres(db)
+
+	mock.ExpectExec("INSERT INTO items_labels \\(item_id, label_id\\) SELECT \\$1, lb.id FROM labels lb (.+) ON CONFLICT DO NOTHING").
+		WithArgs(5, 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.Attach(context.Background(), 1, 5, 2))
+
+	mock.ExpectExec("DELETE FROM items_labels il USING labels lb WHERE (.+)").
+		WithArgs(1, 5, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.Detach(context.Background(), 1, 5, 2))
+
+	mock.ExpectQuery("SELECT (.+) FROM labels lb INNER JOIN items_labels il on (.+) ORDER BY lb.name").
+		WithArgs(1, 5).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color"}).AddRow(2, "home", "#00ff00"))
+	labels, err := r.GetByItem(context.Background(), 1, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.Label{{Id: 2, Name: "home", Color: "#00ff00"}}, labels)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
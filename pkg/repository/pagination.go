@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cursor points at the last row of a page. It is handed to clients as opaque
+// base64 and is only valid for the sort it was issued with.
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v,omitempty"`
+	Id    int    `json:"id"`
+}
+
+var errInvalidCursor = fmt.Errorf("%w: invalid cursor", todo.ErrValidation)
+
+func encodeCursor(sort string, id int, value string) string {
+	b, _ := json.Marshal(cursor{Sort: sort, Id: id, Value: value})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// sortValue returns the value of the sort column of a row for its cursor.
+// Position only applies to items.
+func sortValue(sort, title string, createdAt time.Time, position int64) string {
+	switch sort {
+	case "title":
+		return title
+	case "created_at":
+		return createdAt.Format(time.RFC3339Nano)
+	case "position":
+		return strconv.FormatInt(position, 10)
+	default:
+		return ""
+	}
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+	if err = json.Unmarshal(b, &c); err != nil {
+		return c, errInvalidCursor
+	}
+	return c, nil
+}
+
+// keysetCondition returns the condition resuming a listing after the cursor
+// of the query, using placeholders starting at argId.
+func keysetCondition(alias string, query todo.ListQuery, argId int) (string, []interface{}, error) {
+	if query.Cursor == "" {
+		return "", nil, nil
+	}
+
+	c, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.Sort != query.Sort {
+		return "", nil, fmt.Errorf("%w: cursor does not match sort", todo.ErrValidation)
+	}
+
+	op := ">"
+	if query.Order == "desc" {
+		op = "<"
+	}
+
+	column := sortColumn(alias, query.Sort)
+	if column == alias+".id" {
+		return fmt.Sprintf("%s %s $%d", column, op, argId), []interface{}{c.Id}, nil
+	}
+	return fmt.Sprintf("(%s, %s.id) %s ($%d, $%d)", column, alias, op, argId, argId+1),
+		[]interface{}{c.Value, c.Id}, nil
+}
+
+func orderClause(alias string, query todo.ListQuery) string {
+	order := "ASC"
+	if query.Order == "desc" {
+		order = "DESC"
+	}
+
+	column := sortColumn(alias, query.Sort)
+	if column == alias+".id" {
+		return fmt.Sprintf("ORDER BY %s %s", column, order)
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %s.id %s", column, order, alias, order)
+}
+
+// sortColumn maps a validated sort name to its column, so nothing but known
+// column names is ever formatted into a query. Positions are kept in the
+// lists_items alias li.
+func sortColumn(alias, sort string) string {
+	switch sort {
+	case "title", "created_at":
+		return alias + "." + sort
+	case "position":
+		return "li.position"
+	default:
+		return alias + ".id"
+	}
+}
+
+// likePattern turns user input into an ILIKE substring pattern.
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const (
@@ -11,8 +15,54 @@ const (
 	usersListsTable = "users_lists"
 	todoItemsTable  = "todo_items"
 	listsItemsTable = "lists_items"
+	sessionsTable   = "sessions"
+
+	reminderDeliveriesTable = "reminder_deliveries"
+	activityTable           = "activity"
+	webhooksTable           = "webhooks"
+	webhookDeliveriesTable  = "webhook_deliveries"
+	labelsTable             = "labels"
+	itemsLabelsTable        = "items_labels"
+	itemSeriesTable         = "item_series"
+	calendarFeedsTable      = "calendar_feeds"
+)
+
+// Conditions on the users_lists alias ul deciding who may modify a list.
+var (
+	writeAccess = fmt.Sprintf("ul.role IN ('%s', '%s')", todo.RoleOwner, todo.RoleEditor)
+	ownerAccess = fmt.Sprintf("ul.role = '%s'", todo.RoleOwner)
 )
 
+// uniqueViolation is the Postgres error code of a violated unique constraint.
+const uniqueViolation = "23505"
+
+// notFound replaces sql.ErrNoRows with todo.ErrNotFound for the named entity,
+// so that callers never see driver errors for missing rows.
+func notFound(err error, entity string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s %w", entity, todo.ErrNotFound)
+	}
+	return err
+}
+
+// affected returns todo.ErrNotFound for the named entity when a statement
+// matched no rows, e.g. because the user has no access to them.
+func affected(res sql.Result, entity string) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("%s %w", entity, todo.ErrNotFound)
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
 type Config struct {
 	Host     string
 	Port     string
@@ -22,9 +72,13 @@ type Config struct {
 	SSLMode  string
 }
 
+func (cfg Config) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, cfg.SSLMode)
+}
+
 func NewPostgresDB(cfg Config) (*sqlx.DB, error) {
-	db, err := sqlx.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, cfg.SSLMode))
+	db, err := sqlx.Open("postgres", cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed with sqlx.Open: %s", err)
 	}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type ReminderPostgres struct {
+	db *sqlx.DB
+}
+
+func NewReminderPostgres(db *sqlx.DB) *ReminderPostgres {
+	return &ReminderPostgres{db: db}
+}
+
+// Enqueue records a pending delivery for every user with access to an undone
+// item whose reminder (or due date, if it has no reminder) fell within the
+// last catchUp period. The unique key makes it safe to run on every replica.
+func (r *ReminderPostgres) Enqueue(ctx context.Context, catchUp time.Duration) (int64, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (item_id, user_id, remind_at)
+									SELECT ti.id, ul.user_id, COALESCE(ti.remind_at, ti.due_at) FROM %s ti
+									INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id
+									WHERE ti.done = false AND ti.deleted_at IS NULL AND COALESCE(ti.remind_at, ti.due_at) <= now()
+									AND COALESCE(ti.remind_at, ti.due_at) > now() - make_interval(secs => $1)
+									ON CONFLICT (item_id, user_id, remind_at) DO NOTHING`,
+		reminderDeliveriesTable, todoItemsTable, listsItemsTable, usersListsTable)
+
+	res, err := r.db.ExecContext(ctx, query, catchUp.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue reminders: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Claim leases up to limit pending deliveries to the caller. Rows locked by
+// another replica are skipped, and a delivery whose lease expires without
+// being marked is claimed again, so every reminder is sent at least once.
+func (r *ReminderPostgres) Claim(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]todo.Reminder, error) {
+	var reminders []todo.Reminder
+	query := fmt.Sprintf(`UPDATE %s rd SET attempts = rd.attempts + 1, next_attempt_at = now() + make_interval(secs => $1)
+									FROM (SELECT id FROM %s WHERE sent_at IS NULL AND attempts < $2 AND next_attempt_at <= now()
+										ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED) due, %s ti, %s u
+									WHERE rd.id = due.id AND ti.id = rd.item_id AND u.id = rd.user_id
+									RETURNING rd.id, rd.item_id, rd.user_id, u.username, ti.title, ti.due_at, rd.remind_at, rd.attempts`,
+		reminderDeliveriesTable, reminderDeliveriesTable, todoItemsTable, usersTable)
+
+	if err := r.db.SelectContext(ctx, &reminders, query, lease.Seconds(), maxAttempts, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+func (r *ReminderPostgres) MarkSent(ctx context.Context, id int) error {
+	query := fmt.Sprintf("UPDATE %s SET sent_at = now(), last_error = NULL WHERE id = $1", reminderDeliveriesTable)
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// MarkFailed schedules the next attempt after the retry delay, counted from
+// the database clock like the lease of Claim.
+func (r *ReminderPostgres) MarkFailed(ctx context.Context, id int, reason string, retryIn time.Duration) error {
+	query := fmt.Sprintf("UPDATE %s SET last_error = $1, next_attempt_at = now() + make_interval(secs => $2) WHERE id = $3",
+		reminderDeliveriesTable)
+	_, err := r.db.ExecContext(ctx, query, reason, retryIn.Seconds(), id)
+	return err
+}
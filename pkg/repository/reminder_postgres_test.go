@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestReminderPostgres_Enqueue(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewReminderPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO reminder_deliveries (.+) SELECT (.+) ON CONFLICT (.+) DO NOTHING").
+					WithArgs(float64(3600)).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			want: 2,
+		},
+		{
+			name: "Insert Error",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO reminder_deliveries").
+					WithArgs(float64(3600)).WillReturnError(errors.New("insert error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Enqueue(context.Background(), time.Hour)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReminderPostgres_Claim(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewReminderPostgres(db)
+
+	remindAt := time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "item_id", "user_id", "username", "title", "due_at", "remind_at", "attempts"}).
+		AddRow(1, 2, 3, "test", "title", nil, remindAt, 1)
+	mock.ExpectQuery("UPDATE reminder_deliveries rd SET (.+) FOR UPDATE SKIP LOCKED(.+) RETURNING (.+)").
+		WithArgs(float64(300), 5, 10).WillReturnRows(rows)
+
+	got, err := r.Claim(context.Background(), 10, 5, 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.Reminder{
+		{Id: 1, ItemId: 2, UserId: 3, Username: "test", Title: "title", RemindAt: remindAt, Attempts: 1},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReminderPostgres_MarkFailed(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewReminderPostgres(db)
+
+	mock.ExpectExec("UPDATE reminder_deliveries SET last_error = \\$1, next_attempt_at = now\\(\\) \\+ make_interval\\(secs => \\$2\\) (.+)").
+		WithArgs("timeout", float64(240), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, r.MarkFailed(context.Background(), 1, "timeout", 4*time.Minute))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
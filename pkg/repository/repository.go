@@ -1,41 +1,144 @@
 package repository
 
 import (
+	"context"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/jmoiron/sqlx"
+	"time"
 )
 
 type Authorization interface {
-	CreateUser(user todo.User) (int, error)
-	GetUser(username, password string) (todo.User, error)
+	CreateUser(ctx context.Context, user todo.User) (int, error)
+	GetUser(ctx context.Context, username string) (todo.User, error)
+	UpdatePasswordHash(ctx context.Context, userId int, passwordHash string) error
+	HasLegacyPasswordHashes(ctx context.Context) (bool, error)
+}
+
+type Session interface {
+	Create(ctx context.Context, session todo.Session) (int, error)
+	GetById(ctx context.Context, sessionId int) (todo.Session, error)
+	Rotate(ctx context.Context, sessionId int, oldHash, newHash string, expiresAt time.Time) (bool, error)
+	Revoke(ctx context.Context, sessionId int) error
 }
 
 type TodoList interface {
-	Create(userId int, list todo.TodoList) (int, error)
-	GetAll(userId int) ([]todo.TodoList, error)
-	GetById(userId, listId int) (todo.TodoList, error)
-	Delete(userId, listId int) error
-	Update(userId, listId int, input todo.UpdateListInput) error
+	Create(ctx context.Context, userId int, list todo.TodoList) (int, error)
+	GetAll(ctx context.Context, userId int, query todo.ListQuery) ([]todo.TodoList, todo.PageInfo, error)
+	GetById(ctx context.Context, userId, listId int) (todo.TodoList, error)
+	Delete(ctx context.Context, userId, listId int) error
+	Restore(ctx context.Context, userId, listId int) error
+	Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error
+	Import(ctx context.Context, userId int, list todo.ListExport) (int, error)
+}
+
+type Collaborator interface {
+	Share(ctx context.Context, userId, listId int, username, role string) error
+	GetAll(ctx context.Context, userId, listId int) ([]todo.Collaborator, error)
+	Remove(ctx context.Context, userId, listId, collaboratorId int) error
 }
 
 type TodoItem interface {
-	Create(listId int, item todo.TodoItem) (int, error)
-	GetAll(userId, listId int) ([]todo.TodoItem, error)
-	GetById(userId, itemId int) (todo.TodoItem, error)
-	Delete(userId, itemId int) error
-	Update(userId, itemId int, input todo.UpdateItemInput) error
+	Create(ctx context.Context, userId, listId int, item todo.TodoItem) (int, error)
+	GetAll(ctx context.Context, userId, listId int, query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error)
+	GetAllForUser(ctx context.Context, userId int, query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error)
+	GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error)
+	GetTree(ctx context.Context, userId, listId int) ([]todo.TodoItem, error)
+	GetDue(ctx context.Context, userId int, from *time.Time, before time.Time) ([]todo.TodoItem, error)
+	Delete(ctx context.Context, userId, itemId int) error
+	Restore(ctx context.Context, userId, itemId int) error
+	Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) (int, error)
+	SetRecurrence(ctx context.Context, userId, itemId int, rule todo.Recurrence) error
+	StopRecurrence(ctx context.Context, userId, itemId int) error
+	Reorder(ctx context.Context, userId, listId int, input todo.ReorderItemsInput) error
+	Move(ctx context.Context, userId, itemId, listId int) error
+	Copy(ctx context.Context, userId, itemId, listId int) (int, error)
+	Batch(ctx context.Context, userId, listId int, ops []todo.BatchOperation, partial bool) ([]todo.BatchResult, error)
+	GetDated(ctx context.Context, userId int, listId *int) ([]todo.TodoItem, error)
+}
+
+type Label interface {
+	Create(ctx context.Context, userId int, input todo.LabelInput) (int, error)
+	GetAll(ctx context.Context, userId int) ([]todo.Label, error)
+	GetById(ctx context.Context, userId, labelId int) (todo.Label, error)
+	Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error
+	Delete(ctx context.Context, userId, labelId int) error
+	GetByItem(ctx context.Context, userId, itemId int) ([]todo.Label, error)
+	Attach(ctx context.Context, userId, itemId, labelId int) error
+	Detach(ctx context.Context, userId, itemId, labelId int) error
+}
+
+type Search interface {
+	Search(ctx context.Context, userId int, query todo.SearchQuery) ([]todo.SearchResult, error)
+}
+
+type Trash interface {
+	GetAll(ctx context.Context, userId int) (todo.Trash, error)
+	Purge(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type Activity interface {
+	GetAll(ctx context.Context, listId int, query todo.PageQuery) ([]todo.Activity, todo.PageInfo, error)
+}
+
+type Webhook interface {
+	Create(ctx context.Context, userId int, input todo.WebhookInput) (int, error)
+	GetAll(ctx context.Context, userId int) ([]todo.Webhook, error)
+	GetById(ctx context.Context, userId, webhookId int) (todo.Webhook, error)
+	Update(ctx context.Context, userId, webhookId int, input todo.UpdateWebhookInput) error
+	Delete(ctx context.Context, userId, webhookId int) error
+}
+
+type WebhookDelivery interface {
+	GetAll(ctx context.Context, webhookId int, query todo.PageQuery) ([]todo.WebhookDelivery, todo.PageInfo, error)
+	Enqueue(ctx context.Context, payload todo.WebhookPayload) (int64, error)
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]todo.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id, statusCode int) error
+	MarkFailed(ctx context.Context, id, statusCode int, reason string, retryIn *time.Duration, disableAfter int) error
+}
+
+type Reminder interface {
+	Enqueue(ctx context.Context, catchUp time.Duration) (int64, error)
+	Claim(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]todo.Reminder, error)
+	MarkSent(ctx context.Context, id int) error
+	MarkFailed(ctx context.Context, id int, reason string, retryIn time.Duration) error
+}
+
+type Calendar interface {
+	SetToken(ctx context.Context, userId int, tokenHash string) error
+	DeleteToken(ctx context.Context, userId int) error
+	GetUserId(ctx context.Context, tokenHash string) (int, error)
 }
 
 type Repository struct {
 	Authorization
+	Session
 	TodoList
+	Collaborator
 	TodoItem
+	Label
+	Search
+	Trash
+	Activity
+	Webhook
+	WebhookDelivery
+	Reminder
+	Calendar
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
 	return &Repository{
-		Authorization: NewAuthPostgres(db),
-		TodoList:      NewTodoListPostgres(db),
-		TodoItem:      NewTodoItemPostgres(db),
+		Authorization:   NewAuthPostgres(db),
+		Session:         NewSessionPostgres(db),
+		TodoList:        NewTodoListPostgres(db),
+		Collaborator:    NewCollaboratorPostgres(db),
+		TodoItem:        NewTodoItemPostgres(db),
+		Label:           NewLabelPostgres(db),
+		Search:          NewSearchPostgres(db),
+		Trash:           NewTrashPostgres(db),
+		Activity:        NewActivityPostgres(db),
+		Webhook:         NewWebhookPostgres(db),
+		WebhookDelivery: NewWebhookDeliveryPostgres(db),
+		Reminder:        NewReminderPostgres(db),
+		Calendar:        NewCalendarPostgres(db),
 	}
 }
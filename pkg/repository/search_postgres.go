@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+	"html"
+	"strings"
+)
+
+// Markers of the matched words in the snippets of ts_headline, which are
+// turned into <mark> tags once the rest of the snippet is escaped.
+const (
+	snippetStart = "\x02"
+	snippetStop  = "\x03"
+)
+
+type SearchPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSearchPostgres(db *sqlx.DB) *SearchPostgres {
+	return &SearchPostgres{db: db}
+}
+
+// Search ranks the lists and the items of the lists the user can access that
+// match the query, best matches first.
+func (r *SearchPostgres) Search(ctx context.Context, userId int, query todo.SearchQuery) ([]todo.SearchResult, error) {
+	searchQuery := fmt.Sprintf(`SELECT '%s' AS type, tl.id, tl.id AS list_id, tl.title, %s AS snippet, ts_rank(tl.search, q) AS rank
+									FROM %s tl INNER JOIN %s ul on ul.list_id = tl.id, to_tsquery('simple', $2) q
+									WHERE ul.user_id = $1 AND tl.deleted_at IS NULL AND tl.search @@ q
+								UNION ALL
+								SELECT '%s' AS type, ti.id, li.list_id, ti.title, %s AS snippet, ts_rank(ti.search, q) AS rank
+									FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id
+									INNER JOIN %s tl on tl.id = li.list_id, to_tsquery('simple', $2) q
+									WHERE ul.user_id = $1 AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL AND ti.search @@ q
+								ORDER BY rank DESC, type, id LIMIT $3`,
+		todo.SearchTypeList, headline("tl"), todoListsTable, usersListsTable,
+		todo.SearchTypeItem, headline("ti"), todoItemsTable, listsItemsTable, usersListsTable, todoListsTable)
+
+	var results []todo.SearchResult
+	if err := r.db.SelectContext(ctx, &results, searchQuery, userId, prefixQuery(query.Terms()), query.Limit); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Snippet = markSnippet(results[i].Snippet)
+	}
+	return results, nil
+}
+
+// headline returns the snippet of the title and description of the table
+// alias matching the tsquery q.
+func headline(alias string) string {
+	return fmt.Sprintf(`ts_headline('simple', concat_ws(' ', %s.title, %s.description), q,
+		'StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=20, MinWords=5')`, alias, alias, snippetStart, snippetStop)
+}
+
+// prefixQuery returns a tsquery matching words starting with every term. The
+// terms only have letters and digits, so they need no quoting.
+func prefixQuery(terms []string) string {
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+	return strings.Join(prefixes, " & ")
+}
+
+// markSnippet escapes a snippet of ts_headline for HTML and wraps the matched
+// words in <mark> tags.
+func markSnippet(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, snippetStart, "<mark>")
+	return strings.ReplaceAll(snippet, snippetStop, "</mark>")
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestSearchPostgres_Search(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewSearchPostgres(db)
+
+	rows := sqlmock.NewRows([]string{"type", "id", "list_id", "title", "snippet", "rank"}).
+		AddRow(todo.SearchTypeItem, 4, 1, "Buy milk", "Buy \x02milk\x03 & <bread>", 0.6).
+		AddRow(todo.SearchTypeList, 1, 1, "Shopping", "Shopping for \x02milk\x03", 0.2)
+	mock.ExpectQuery("SELECT 'list' AS type, (.+) FROM todo_lists tl INNER JOIN users_lists ul (.+) UNION ALL "+
+		"SELECT 'item' AS type, (.+) ORDER BY rank DESC, type, id LIMIT \\$3").
+		WithArgs(1, "buy:* & mil:*", 20).WillReturnRows(rows)
+
+	results, err := r.Search(context.Background(), 1, todo.SearchQuery{Q: "buy, mil!", Limit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.SearchResult{
+		{Type: todo.SearchTypeItem, Id: 4, ListId: 1, Title: "Buy milk", Snippet: "Buy <mark>milk</mark> &amp; &lt;bread&gt;", Rank: 0.6},
+		{Type: todo.SearchTypeList, Id: 1, ListId: 1, Title: "Shopping", Snippet: "Shopping for <mark>milk</mark>", Rank: 0.2},
+	}, results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPrefixQuery(t *testing.T) {
+	assert.Equal(t, "buy:*", prefixQuery(todo.SearchQuery{Q: "buy"}.Terms()))
+	assert.Equal(t, "e:* & mail:* & 42:*", prefixQuery(todo.SearchQuery{Q: " e-mail, (42) "}.Terms()))
+}
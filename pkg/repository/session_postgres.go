@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type SessionPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSessionPostgres(db *sqlx.DB) *SessionPostgres {
+	return &SessionPostgres{db: db}
+}
+
+func (r *SessionPostgres) Create(ctx context.Context, session todo.Session) (int, error) {
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (user_id, refresh_token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id",
+		sessionsTable)
+
+	row := r.db.QueryRowContext(ctx, query, session.UserId, session.RefreshTokenHash, session.ExpiresAt)
+	if err := row.Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to create session: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SessionPostgres) GetById(ctx context.Context, sessionId int) (todo.Session, error) {
+	var session todo.Session
+	query := fmt.Sprintf(`SELECT id, user_id, refresh_token_hash, previous_token_hash, expires_at, created_at, revoked_at
+									FROM %s WHERE id = $1`,
+		sessionsTable)
+	err := r.db.GetContext(ctx, &session, query, sessionId)
+	if err != nil {
+		return session, notFound(fmt.Errorf("failed to get session: %w", err), "session")
+	}
+	return session, nil
+}
+
+// Rotate swaps the refresh token hash of an active session only if it still
+// holds oldHash, so two concurrent refreshes with the same token cannot both win.
+// oldHash is kept to recognize the rotated token if it is presented again.
+func (r *SessionPostgres) Rotate(ctx context.Context, sessionId int, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET previous_token_hash = refresh_token_hash, refresh_token_hash = $1, expires_at = $2
+									WHERE id = $3 AND refresh_token_hash = $4 AND revoked_at IS NULL`, sessionsTable)
+	res, err := r.db.ExecContext(ctx, query, newHash, expiresAt, sessionId, oldHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate session: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (r *SessionPostgres) Revoke(ctx context.Context, sessionId int) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL", sessionsTable)
+	_, err := r.db.ExecContext(ctx, query, sessionId)
+	return err
+}
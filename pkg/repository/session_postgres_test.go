@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestSessionPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewSessionPostgres(db)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   todo.Session
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+				mock.ExpectQuery("INSERT INTO sessions").
+					WithArgs(1, "hash", expiresAt).WillReturnRows(rows)
+			},
+			input: todo.Session{
+				UserId:           1,
+				RefreshTokenHash: "hash",
+				ExpiresAt:        expiresAt,
+			},
+			want: 1,
+		},
+		{
+			name: "Insert Error",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO sessions").
+					WithArgs(404, "hash", expiresAt).WillReturnError(errors.New("insert error"))
+			},
+			input: todo.Session{
+				UserId:           404,
+				RefreshTokenHash: "hash",
+				ExpiresAt:        expiresAt,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Create(context.Background(), testCase.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSessionPostgres_GetById(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewSessionPostgres(db)
+
+	createdAt := time.Now()
+	expiresAt := createdAt.Add(time.Hour)
+	previousHash := "previous"
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   int
+		want    todo.Session
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "refresh_token_hash", "previous_token_hash", "expires_at", "created_at", "revoked_at"}).
+					AddRow(1, 2, "hash", "previous", expiresAt, createdAt, nil)
+				mock.ExpectQuery("SELECT (.+) FROM sessions WHERE (.+)").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: 1,
+			want: todo.Session{
+				Id:                1,
+				UserId:            2,
+				RefreshTokenHash:  "hash",
+				PreviousTokenHash: &previousHash,
+				ExpiresAt:         expiresAt,
+				CreatedAt:         createdAt,
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "refresh_token_hash", "previous_token_hash", "expires_at", "created_at", "revoked_at"})
+				mock.ExpectQuery("SELECT (.+) FROM sessions WHERE (.+)").
+					WithArgs(404).WillReturnRows(rows)
+			},
+			input:   404,
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetById(context.Background(), testCase.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSessionPostgres_Rotate(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewSessionPostgres(db)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE sessions SET previous_token_hash = refresh_token_hash, refresh_token_hash = \\$1(.+) WHERE (.+)").
+					WithArgs("new", expiresAt, 1, "old").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "Already Rotated",
+			mock: func() {
+				mock.ExpectExec("UPDATE sessions SET (.+) WHERE (.+)").
+					WithArgs("new", expiresAt, 1, "old").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want: false,
+		},
+		{
+			name: "Update Error",
+			mock: func() {
+				mock.ExpectExec("UPDATE sessions SET (.+) WHERE (.+)").
+					WithArgs("new", expiresAt, 1, "old").WillReturnError(errors.New("update error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Rotate(context.Background(), 1, "old", "new", expiresAt)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
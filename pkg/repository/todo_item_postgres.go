@@ -1,12 +1,26 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"strings"
+	"time"
 )
 
+// positionGap is the distance between the positions of neighbouring items
+// when they are appended or renumbered, which leaves room to move items in
+// between without touching the others.
+const positionGap = 1024
+
+var itemColumns = fmt.Sprintf(`ti.id, ti.parent_id, ti.title, ti.description, ti.done, ti.auto_complete, ti.due_at, ti.priority, ti.remind_at,
+	ti.series_id, (SELECT s.rule FROM %s s WHERE s.id = ti.series_id) AS recurrence, ti.created_at, ti.updated_at, ti.deleted_at, li.list_id, li.position`,
+	itemSeriesTable)
+
 type TodoItemPostgres struct {
 	db *sqlx.DB
 }
@@ -15,95 +29,1172 @@ func NewTodoItemPostgres(db *sqlx.DB) *TodoItemPostgres {
 	return &TodoItemPostgres{db: db}
 }
 
-func (r *TodoItemPostgres) Create(listId int, item todo.TodoItem) (int, error) {
-	tx, err := r.db.Begin()
+func (r *TodoItemPostgres) Create(ctx context.Context, userId, listId int, item todo.TodoItem) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 
-	var itemId int
-	createItemQuery := fmt.Sprintf("INSERT INTO %s (title, description) values ($1, $2) RETURNING id", todoItemsTable)
-
-	row := tx.QueryRow(createItemQuery, item.Title, item.Description)
-	err = row.Scan(&itemId)
+	itemId, err := createItem(ctx, tx, userId, listId, item)
 	if err != nil {
 		tx.Rollback()
 		return 0, err
 	}
 
-	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id) values ($1, $2)", listsItemsTable)
-	_, err = tx.Exec(createListItemsQuery, listId, itemId)
+	return itemId, tx.Commit()
+}
+
+func createItem(ctx context.Context, tx *sqlx.Tx, userId, listId int, item todo.TodoItem) (int, error) {
+	if item.ParentId != nil {
+		if err := checkParent(ctx, tx, listId, *item.ParentId); err != nil {
+			return 0, err
+		}
+	}
+
+	// the series is only ever set from createSeries, one given by the client
+	// could belong to another user
+	item.SeriesId = nil
+	if item.Recurrence != nil {
+		rule := item.Recurrence.Anchor(item.DueAt)
+		seriesId, err := createSeries(ctx, tx, rule)
+		if err != nil {
+			return 0, err
+		}
+		item.SeriesId, item.Recurrence = &seriesId, &rule
+	}
+
+	itemId, err := insertItem(ctx, tx, item)
 	if err != nil {
-		tx.Rollback()
 		return 0, err
 	}
 
-	return itemId, tx.Commit()
+	createListItemsQuery := fmt.Sprintf(`INSERT INTO %s (list_id, item_id, position)
+									SELECT ul.list_id, $1, COALESCE((SELECT max(position) FROM %s WHERE list_id = $2), 0) + %d FROM %s ul
+									WHERE ul.list_id = $2 AND ul.user_id = $3 AND %s`,
+		listsItemsTable, listsItemsTable, positionGap, usersListsTable, writeAccess)
+	res, err := tx.ExecContext(ctx, createListItemsQuery, itemId, listId, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	linked, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if linked == 0 {
+		return 0, fmt.Errorf("%w: list is read-only for this user", todo.ErrForbidden)
+	}
+
+	err = logActivity(ctx, tx, todo.Activity{
+		ListId: listId, ItemId: &itemId, UserId: &userId, Action: todo.ActionItemCreated, Changes: createdChanges(item),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// a new subtask is not done, which reopens an auto-completed parent
+	return itemId, syncCompletion(ctx, tx, userId, item.ParentId)
 }
 
-func (r *TodoItemPostgres) GetAll(userId, listId int) ([]todo.TodoItem, error) {
-	var items []todo.TodoItem
-	query := fmt.Sprintf(`SELECT ti.id, ti.title, ti.description, ti.done FROM %s ti INNER JOIN %s li on li.item_id = ti.id
-									INNER JOIN %s ul on ul.list_id = li.list_id WHERE li.list_id = $1 AND ul.user_id = $2`,
+func (r *TodoItemPostgres) GetAll(ctx context.Context, userId, listId int, query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error) {
+	from := fmt.Sprintf(`%s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id`,
 		todoItemsTable, listsItemsTable, usersListsTable)
-	if err := r.db.Select(&items, query, listId, userId); err != nil {
+	conditions, args := itemFilters([]string{"li.list_id = $1", "ul.user_id = $2", "ti.deleted_at IS NULL"},
+		[]interface{}{listId, userId}, query, 2)
+
+	return r.getPage(ctx, from, conditions, args, query)
+}
+
+// GetAllForUser returns the items of every list the user can access.
+func (r *TodoItemPostgres) GetAllForUser(ctx context.Context, userId int, query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error) {
+	from := fmt.Sprintf(`%s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id`,
+		todoItemsTable, listsItemsTable, usersListsTable, todoListsTable)
+	conditions, args := itemFilters([]string{"ul.user_id = $1", "ti.deleted_at IS NULL", "tl.deleted_at IS NULL"},
+		[]interface{}{userId}, query, 1)
+
+	return r.getPage(ctx, from, conditions, args, query)
+}
+
+// itemFilters adds the filters of the query to the conditions and arguments
+// of an items query, in which the user is the argument $userArg.
+func itemFilters(conditions []string, args []interface{}, query todo.ListQuery, userArg int) ([]string, []interface{}) {
+	argId := len(args) + 1
+
+	if query.Title != "" {
+		conditions = append(conditions, fmt.Sprintf("ti.title ILIKE $%d", argId))
+		args = append(args, likePattern(query.Title))
+		argId++
+	}
+
+	if query.Done != nil {
+		conditions = append(conditions, fmt.Sprintf("ti.done = $%d", argId))
+		args = append(args, *query.Done)
+		argId++
+	}
+
+	if len(query.Labels) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`ti.id IN (SELECT il.item_id FROM %s il INNER JOIN %s lb on lb.id = il.label_id
+									WHERE lb.user_id = $%d AND lb.name = ANY($%d) GROUP BY il.item_id HAVING count(*) = $%d)`,
+			itemsLabelsTable, labelsTable, userArg, argId, argId+1))
+		args = append(args, pq.Array(query.Labels), len(query.Labels))
+	}
+
+	return conditions, args
+}
+
+// getPage counts and selects the items matching the conditions in from.
+func (r *TodoItemPostgres) getPage(ctx context.Context, from string, conditions []string, args []interface{},
+	query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error) {
+	var page todo.PageInfo
+	argId := len(args) + 1
+
+	countQuery := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s", from, strings.Join(conditions, " AND "))
+	if err := r.db.GetContext(ctx, &page.Total, countQuery, args...); err != nil {
+		return nil, page, err
+	}
+
+	keyset, keysetArgs, err := keysetCondition("ti", query, argId)
+	if err != nil {
+		return nil, page, err
+	}
+	if keyset != "" {
+		conditions = append(conditions, keyset)
+		args = append(args, keysetArgs...)
+		argId += len(keysetArgs)
+	}
+
+	var items []todo.TodoItem
+	selectQuery := fmt.Sprintf("SELECT %s FROM %s WHERE %s %s LIMIT $%d",
+		itemColumns, from, strings.Join(conditions, " AND "), orderClause("ti", query), argId)
+	args = append(args, query.Limit+1)
+
+	if err = r.db.SelectContext(ctx, &items, selectQuery, args...); err != nil {
+		return nil, page, err
+	}
+
+	if len(items) > query.Limit {
+		items = items[:query.Limit]
+		last := items[len(items)-1]
+		page.NextCursor = encodeCursor(query.Sort, last.Id, sortValue(query.Sort, last.Title, last.CreatedAt, last.Position))
+	}
+	return items, page, nil
+}
+
+// GetDue returns the undone items of every list the user can access that are
+// due before the given time, and not before from when it is set.
+func (r *TodoItemPostgres) GetDue(ctx context.Context, userId int, from *time.Time, before time.Time) ([]todo.TodoItem, error) {
+	conditions := []string{"ul.user_id = $1", "ti.deleted_at IS NULL", "ti.done = false", "ti.due_at < $2"}
+	args := []interface{}{userId, before}
+
+	if from != nil {
+		conditions = append(conditions, "ti.due_at >= $3")
+		args = append(args, *from)
+	}
+
+	var items []todo.TodoItem
+	query := fmt.Sprintf(`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id WHERE %s ORDER BY ti.due_at, ti.priority DESC, ti.id`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, strings.Join(conditions, " AND "))
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetDated returns the items with a due time in the lists of the user, or in
+// one of them, done ones included.
+func (r *TodoItemPostgres) GetDated(ctx context.Context, userId int, listId *int) ([]todo.TodoItem, error) {
+	conditions := []string{"ul.user_id = $1", "ti.due_at IS NOT NULL", "ti.deleted_at IS NULL", "tl.deleted_at IS NULL"}
+	args := []interface{}{userId}
+
+	if listId != nil {
+		conditions = append(conditions, "li.list_id = $2")
+		args = append(args, *listId)
+	}
+
+	var items []todo.TodoItem
+	query := fmt.Sprintf(`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+									WHERE %s ORDER BY ti.due_at, ti.id`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, todoListsTable, strings.Join(conditions, " AND "))
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
 		return nil, err
 	}
 
 	return items, nil
 }
 
-func (r *TodoItemPostgres) GetById(userId, itemId int) (todo.TodoItem, error) {
+func (r *TodoItemPostgres) GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error) {
 	var item todo.TodoItem
-	query := fmt.Sprintf(`SELECT ti.id, ti.title, ti.description, ti.done FROM %s ti INNER JOIN %s li on li.item_id = ti.id
-									INNER JOIN %s ul on ul.list_id = li.list_id WHERE ti.id = $1 AND ul.user_id = $2`,
-		todoItemsTable, listsItemsTable, usersListsTable)
-	if err := r.db.Get(&item, query, itemId, userId); err != nil {
-		return item, err
+	query := fmt.Sprintf(`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id WHERE ti.id = $1 AND ul.user_id = $2 AND ti.deleted_at IS NULL`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable)
+	if err := r.db.GetContext(ctx, &item, query, itemId, userId); err != nil {
+		return item, notFound(err, "item")
 	}
 
 	return item, nil
 }
 
-func (r *TodoItemPostgres) Delete(userId, itemId int) error {
-	query := fmt.Sprintf(`DELETE FROM %s ti USING %s li, %s ul
-		WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $1 AND ti.id = $2`,
-		todoItemsTable, listsItemsTable, usersListsTable)
+// GetTree returns every item of the list in order, to be nested under their
+// parents.
+func (r *TodoItemPostgres) GetTree(ctx context.Context, userId, listId int) ([]todo.TodoItem, error) {
+	var items []todo.TodoItem
+	query := fmt.Sprintf(`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id
+									WHERE li.list_id = $1 AND ul.user_id = $2 AND ti.deleted_at IS NULL ORDER BY li.position, ti.id`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable)
+	if err := r.db.SelectContext(ctx, &items, query, listId, userId); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// subtreeQuery selects the ids of the subtasks of the item $1 at any depth.
+var subtreeQuery = fmt.Sprintf(`WITH RECURSIVE subtree AS (
+		SELECT id FROM %s WHERE parent_id = $1
+		UNION ALL SELECT c.id FROM %s c INNER JOIN subtree s on c.parent_id = s.id)`,
+	todoItemsTable, todoItemsTable)
+
+// Queries of Delete and Restore, see setDeleted.
+var (
+	deleteItemQuery = fmt.Sprintf(`UPDATE %s ti SET deleted_at = now() FROM %s li, %s ul
+		WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $1 AND ti.id = $2 AND %s
+		AND ti.deleted_at IS NULL RETURNING li.list_id, ti.parent_id, ti.deleted_at`,
+		todoItemsTable, listsItemsTable, usersListsTable, writeAccess)
+	deleteSubtreeQuery = fmt.Sprintf(`%s UPDATE %s ti SET deleted_at = $2 FROM subtree s
+		WHERE ti.id = s.id AND ti.deleted_at IS NULL`, subtreeQuery, todoItemsTable)
+
+	restoreItemQuery = fmt.Sprintf(`UPDATE %s ti SET deleted_at = NULL FROM %s li, %s ul, %s tl, %s prev
+		WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND tl.id = li.list_id AND prev.id = ti.id
+		AND ul.user_id = $1 AND ti.id = $2 AND %s AND ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM %s p WHERE p.id = ti.parent_id AND p.deleted_at IS NOT NULL)
+		RETURNING li.list_id, ti.parent_id, prev.deleted_at`,
+		todoItemsTable, listsItemsTable, usersListsTable, todoListsTable, todoItemsTable, writeAccess, todoItemsTable)
+	restoreSubtreeQuery = fmt.Sprintf(`%s UPDATE %s ti SET deleted_at = NULL FROM subtree s
+		WHERE ti.id = s.id AND ti.deleted_at = $2`, subtreeQuery, todoItemsTable)
+)
+
+// Delete moves the item to the trash, along with its subtasks.
+func (r *TodoItemPostgres) Delete(ctx context.Context, userId, itemId int) error {
+	return r.setDeleted(ctx, userId, itemId, deleteItemQuery, deleteSubtreeQuery, todo.ActionItemDeleted)
+}
+
+// Restore brings a deleted item back out of the trash, with the subtasks that
+// were deleted along with it. Items of a deleted list or parent can only be
+// restored by restoring that one.
+func (r *TodoItemPostgres) Restore(ctx context.Context, userId, itemId int) error {
+	return r.setDeleted(ctx, userId, itemId, restoreItemQuery, restoreSubtreeQuery, todo.ActionItemRestored)
+}
+
+func (r *TodoItemPostgres) setDeleted(ctx context.Context, userId, itemId int, query, cascadeQuery, action string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = markDeleted(ctx, tx, userId, itemId, query, cascadeQuery, action); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// markDeleted runs a Delete or Restore query, which returns the list and the
+// parent of the item and the time it was deleted at, then applies the
+// cascadeQuery to its subtasks and records the action in the activity of the
+// list, whose id it returns.
+func markDeleted(ctx context.Context, tx *sqlx.Tx, userId, itemId int, query, cascadeQuery, action string) (int, error) {
+	var listId int
+	var parentId *int
+	var deletedAt time.Time
+	if err := tx.QueryRowContext(ctx, query, userId, itemId).Scan(&listId, &parentId, &deletedAt); err != nil {
+		return 0, notFound(err, "item")
+	}
+
+	if _, err := tx.ExecContext(ctx, cascadeQuery, itemId, deletedAt); err != nil {
+		return 0, err
+	}
+
+	err := logActivity(ctx, tx, todo.Activity{ListId: listId, ItemId: &itemId, UserId: &userId, Action: action})
+	if err != nil {
+		return 0, err
+	}
+
+	return listId, syncCompletion(ctx, tx, userId, parentId)
+}
+
+// Reorder moves items of a list as described by todo.ReorderItemsInput. Only
+// the moved items get new positions, unless there is no room left between
+// their neighbours and the whole list is renumbered.
+func (r *TodoItemPostgres) Reorder(ctx context.Context, userId, listId int, input todo.ReorderItemsInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = lockList(ctx, tx, userId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var current []itemPosition
+	positionsQuery := fmt.Sprintf(`SELECT li.item_id, li.position FROM %s li INNER JOIN %s ti on ti.id = li.item_id
+									WHERE li.list_id = $1 AND ti.deleted_at IS NULL ORDER BY li.position, li.item_id`,
+		listsItemsTable, todoItemsTable)
+	if err = tx.SelectContext(ctx, &current, positionsQuery, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	moved, err := reorderPositions(current, input)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	itemIds := make([]int64, len(moved))
+	positions := make([]int64, len(moved))
+	for i, p := range moved {
+		itemIds[i] = int64(p.ItemId)
+		positions[i] = p.Position
+	}
+	updateQuery := fmt.Sprintf(`UPDATE %s li SET position = u.position FROM unnest($2::int[], $3::bigint[]) AS u(item_id, position)
+									WHERE li.list_id = $1 AND li.item_id = u.item_id`, listsItemsTable)
+	if _, err = tx.ExecContext(ctx, updateQuery, listId, pq.Array(itemIds), pq.Array(positions)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	changes := todo.Changes{"item_ids": {To: input.ItemIds}}
+	err = logActivity(ctx, tx, todo.Activity{ListId: listId, UserId: &userId, Action: todo.ActionItemsReordered, Changes: changes})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Move links the item and its subtasks to another list, at its end. A moved
+// subtask becomes a top-level item. The user needs write access to both lists.
+func (r *TodoItemPostgres) Move(ctx context.Context, userId, itemId, listId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	item, err := lockItem(ctx, tx, userId, itemId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if item.ListId == listId {
+		tx.Rollback()
+		return fmt.Errorf("%w: item is already in list %d", todo.ErrValidation, listId)
+	}
+
+	if err = lockList(ctx, tx, userId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	moveQuery := fmt.Sprintf(`%s UPDATE %s li SET list_id = $2, position = COALESCE((SELECT max(position) FROM %s WHERE list_id = $2), 0) + moved.rn * %d
+									FROM (SELECT l.item_id, row_number() OVER (ORDER BY l.position, l.item_id) AS rn FROM %s l
+										WHERE l.item_id = $1 OR l.item_id IN (SELECT id FROM subtree)) moved
+									WHERE li.item_id = moved.item_id`,
+		subtreeQuery, listsItemsTable, listsItemsTable, positionGap, listsItemsTable)
+	if _, err = tx.ExecContext(ctx, moveQuery, itemId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if item.ParentId != nil {
+		detachQuery := fmt.Sprintf("UPDATE %s SET parent_id = NULL, updated_at = now() WHERE id = $1", todoItemsTable)
+		if _, err = tx.ExecContext(ctx, detachQuery, itemId); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err = syncCompletion(ctx, tx, userId, item.ParentId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// both lists keep the move in their history
+	changes := todo.Changes{"list_id": {From: item.ListId, To: listId}}
+	for _, id := range []int{item.ListId, listId} {
+		err = logActivity(ctx, tx, todo.Activity{
+			ListId: id, ItemId: &itemId, UserId: &userId, Action: todo.ActionItemMoved, Changes: changes,
+		})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Copy creates a top-level copy of the item, without its subtasks, at the end
+// of another list or of its own one. The user needs access to the item and
+// write access to the list.
+func (r *TodoItemPostgres) Copy(ctx context.Context, userId, itemId, listId int) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var item todo.TodoItem
+	selectQuery := fmt.Sprintf(`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id WHERE ti.id = $1 AND ul.user_id = $2 AND ti.deleted_at IS NULL`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable)
+	if err = tx.GetContext(ctx, &item, selectQuery, itemId, userId); err != nil {
+		tx.Rollback()
+		return 0, notFound(err, "item")
+	}
+
+	if err = lockList(ctx, tx, userId, listId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var copyId int
+	createItemQuery := fmt.Sprintf(`INSERT INTO %s (title, description, done, due_at, priority, remind_at)
+									values ($1, $2, $3, $4, $5, $6) RETURNING id`, todoItemsTable)
+	row := tx.QueryRowContext(ctx, createItemQuery, item.Title, item.Description, item.Done, item.DueAt, item.Priority, item.RemindAt)
+	if err = row.Scan(&copyId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	createListItemsQuery := fmt.Sprintf(`INSERT INTO %s (list_id, item_id, position)
+									values ($1, $2, COALESCE((SELECT max(position) FROM %s WHERE list_id = $1), 0) + %d)`,
+		listsItemsTable, listsItemsTable, positionGap)
+	if _, err = tx.ExecContext(ctx, createListItemsQuery, listId, copyId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	changes := todo.Changes{}
+	addChange(changes, "copied_from", nil, itemId)
+	addChange(changes, "title", nil, item.Title)
+	addChange(changes, "description", nil, item.Description)
+	addChange(changes, "done", false, item.Done)
+	addChange(changes, "due_at", nil, timeValue(item.DueAt))
+	addChange(changes, "priority", nil, item.Priority)
+	addChange(changes, "remind_at", nil, timeValue(item.RemindAt))
+	err = logActivity(ctx, tx, todo.Activity{
+		ListId: listId, ItemId: &copyId, UserId: &userId, Action: todo.ActionItemCreated, Changes: changes,
+	})
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return copyId, tx.Commit()
+}
+
+func insertItem(ctx context.Context, tx *sqlx.Tx, item todo.TodoItem) (int, error) {
+	var itemId int
+	query := fmt.Sprintf(`INSERT INTO %s (parent_id, title, description, auto_complete, due_at, priority, remind_at, series_id)
+									values ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`, todoItemsTable)
+	row := tx.QueryRowContext(ctx, query, item.ParentId, item.Title, item.Description, item.AutoComplete,
+		item.DueAt, item.Priority, item.RemindAt, item.SeriesId)
+	err := row.Scan(&itemId)
+	return itemId, err
+}
+
+func createdChanges(item todo.TodoItem) todo.Changes {
+	changes := todo.Changes{}
+	if item.ParentId != nil {
+		addChange(changes, "parent_id", nil, *item.ParentId)
+	}
+	addChange(changes, "title", nil, item.Title)
+	addChange(changes, "description", nil, item.Description)
+	addChange(changes, "auto_complete", false, item.AutoComplete)
+	addChange(changes, "due_at", nil, timeValue(item.DueAt))
+	addChange(changes, "priority", nil, item.Priority)
+	addChange(changes, "remind_at", nil, timeValue(item.RemindAt))
+	if item.Recurrence != nil {
+		changes["recurrence"] = todo.Change{From: nil, To: *item.Recurrence}
+	}
+	return changes
+}
+
+func createSeries(ctx context.Context, tx *sqlx.Tx, rule todo.Recurrence) (int, error) {
+	var seriesId int
+	query := fmt.Sprintf("INSERT INTO %s (rule) values ($1) RETURNING id", itemSeriesTable)
+	err := tx.QueryRowContext(ctx, query, rule).Scan(&seriesId)
+	return seriesId, err
+}
+
+// createOccurrence creates the occurrence of the series of the item that
+// follows it, at the end of its list, unless the series still has an open
+// one. It returns the id of the new item, or 0. Reminders keep their distance
+// to the due time.
+func createOccurrence(ctx context.Context, tx *sqlx.Tx, userId int, item todo.TodoItem, doneAt time.Time) (int, error) {
+	var open bool
+	openQuery := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE series_id = $1 AND id <> $2 AND NOT done AND deleted_at IS NULL)`,
+		todoItemsTable)
+	if err := tx.GetContext(ctx, &open, openQuery, *item.SeriesId, item.Id); err != nil || open {
+		return 0, err
+	}
 
-	_, err := r.db.Exec(query, userId, itemId)
-	return err
+	next := item
+	dueAt := item.Recurrence.Next(item.DueAt, doneAt)
+	next.DueAt = &dueAt
+
+	// monthly series of items without a due time keep the day of the first
+	// occurrence that has one
+	if rule := item.Recurrence.Anchor(&dueAt); rule.MonthDay != item.Recurrence.MonthDay {
+		query := fmt.Sprintf("UPDATE %s SET rule = $1, updated_at = now() WHERE id = $2", itemSeriesTable)
+		if _, err := tx.ExecContext(ctx, query, rule, *item.SeriesId); err != nil {
+			return 0, err
+		}
+	}
+	if item.RemindAt != nil {
+		remindAt := dueAt
+		if item.DueAt != nil {
+			remindAt = dueAt.Add(item.RemindAt.Sub(*item.DueAt))
+		}
+		next.RemindAt = &remindAt
+	}
+
+	nextId, err := insertItem(ctx, tx, next)
+	if err != nil {
+		return 0, err
+	}
+
+	createListItemsQuery := fmt.Sprintf(`INSERT INTO %s (list_id, item_id, position)
+									values ($1, $2, COALESCE((SELECT max(position) FROM %s WHERE list_id = $1), 0) + %d)`,
+		listsItemsTable, listsItemsTable, positionGap)
+	if _, err = tx.ExecContext(ctx, createListItemsQuery, item.ListId, nextId); err != nil {
+		return 0, err
+	}
+
+	labelsQuery := fmt.Sprintf("INSERT INTO %s (item_id, label_id) SELECT $1, label_id FROM %s WHERE item_id = $2",
+		itemsLabelsTable, itemsLabelsTable)
+	if _, err = tx.ExecContext(ctx, labelsQuery, nextId, item.Id); err != nil {
+		return 0, err
+	}
+
+	next.Recurrence = nil
+	changes := createdChanges(next)
+	addChange(changes, "series_id", nil, *item.SeriesId)
+	err = logActivity(ctx, tx, todo.Activity{
+		ListId: item.ListId, ItemId: &nextId, UserId: &userId, Action: todo.ActionItemCreated, Changes: changes,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// the occurrence is an open subtask, which reopens an auto-completed parent
+	return nextId, syncCompletion(ctx, tx, userId, item.ParentId)
+}
+
+// checkParent makes sure that the item can take a new subtask in the list.
+func checkParent(ctx context.Context, tx *sqlx.Tx, listId, parentId int) error {
+	var parent struct {
+		ListId int `db:"list_id"`
+		Depth  int `db:"depth"`
+	}
+	query := fmt.Sprintf(`WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM %s WHERE id = $1
+			UNION ALL SELECT ti.id, ti.parent_id FROM %s ti INNER JOIN ancestors a on ti.id = a.parent_id)
+		SELECT li.list_id, (SELECT count(*) FROM ancestors) AS depth FROM %s li INNER JOIN %s ti on ti.id = li.item_id
+		WHERE li.item_id = $1 AND ti.deleted_at IS NULL`,
+		todoItemsTable, todoItemsTable, listsItemsTable, todoItemsTable)
+	err := tx.GetContext(ctx, &parent, query, parentId)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && parent.ListId != listId {
+		return fmt.Errorf("%w: parent item %d is not in the list", todo.ErrValidation, parentId)
+	}
+	if err != nil {
+		return err
+	}
+	if parent.Depth >= todo.MaxItemDepth {
+		return fmt.Errorf("%w: subtasks can be nested at most %d levels deep", todo.ErrValidation, todo.MaxItemDepth)
+	}
+	return nil
+}
+
+// syncCompletion completes or reopens the item if it auto-completes and the
+// done state of its subtasks disagrees with its own, then does the same for
+// its parents. The changes are recorded in the activity as made by the user.
+func syncCompletion(ctx context.Context, tx *sqlx.Tx, userId int, itemId *int) error {
+	query := fmt.Sprintf(`SELECT ti.id, ti.parent_id, ti.done, ti.auto_complete, li.list_id,
+			(SELECT bool_and(c.done) FROM %s c WHERE c.parent_id = ti.id AND c.deleted_at IS NULL) AS children_done
+		FROM %s ti INNER JOIN %s li on li.item_id = ti.id WHERE ti.id = $1 FOR UPDATE OF ti`,
+		todoItemsTable, todoItemsTable, listsItemsTable)
+	updateQuery := fmt.Sprintf("UPDATE %s SET done = $1, updated_at = now() WHERE id = $2", todoItemsTable)
+
+	for itemId != nil {
+		var item struct {
+			Id           int   `db:"id"`
+			ParentId     *int  `db:"parent_id"`
+			Done         bool  `db:"done"`
+			AutoComplete bool  `db:"auto_complete"`
+			ListId       int   `db:"list_id"`
+			ChildrenDone *bool `db:"children_done"`
+		}
+		if err := tx.GetContext(ctx, &item, query, *itemId); err != nil {
+			return err
+		}
+		if !item.AutoComplete || item.ChildrenDone == nil || *item.ChildrenDone == item.Done {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, updateQuery, *item.ChildrenDone, item.Id); err != nil {
+			return err
+		}
+		action := todo.ActionItemUpdated
+		if *item.ChildrenDone {
+			action = todo.ActionItemDone
+		}
+		err := logActivity(ctx, tx, todo.Activity{
+			ListId: item.ListId, ItemId: &item.Id, UserId: &userId, Action: action,
+			Changes: todo.Changes{"done": {From: item.Done, To: *item.ChildrenDone}},
+		})
+		if err != nil {
+			return err
+		}
+
+		itemId = item.ParentId
+	}
+	return nil
+}
+
+// lockList locks a list the user can write to until the end of the
+// transaction, which serializes the changes to the positions of its items.
+func lockList(ctx context.Context, tx *sqlx.Tx, userId, listId int) error {
+	query := fmt.Sprintf(`SELECT tl.id FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id
+									WHERE ul.user_id = $1 AND ul.list_id = $2 AND %s AND tl.deleted_at IS NULL FOR UPDATE OF tl`,
+		todoListsTable, usersListsTable, writeAccess)
+	var id int
+	if err := tx.GetContext(ctx, &id, query, userId, listId); err != nil {
+		return notFound(err, "list")
+	}
+	return nil
+}
+
+type itemPosition struct {
+	ItemId   int   `db:"item_id"`
+	Position int64 `db:"position"`
 }
 
-func (r *TodoItemPostgres) Update(userId, itemId int, input todo.UpdateItemInput) error {
+// reorderPositions returns the new positions of the items that have to be
+// updated to apply the input to a list ordered by position.
+func reorderPositions(current []itemPosition, input todo.ReorderItemsInput) ([]itemPosition, error) {
+	inList := make(map[int]bool, len(current))
+	for _, p := range current {
+		inList[p.ItemId] = true
+	}
+	movedIds := make(map[int]bool, len(input.ItemIds))
+	for _, id := range input.ItemIds {
+		if !inList[id] {
+			return nil, fmt.Errorf("%w: item %d is not in the list", todo.ErrValidation, id)
+		}
+		movedIds[id] = true
+	}
+	if input.AfterId != nil && !inList[*input.AfterId] {
+		return nil, fmt.Errorf("%w: item %d is not in the list", todo.ErrValidation, *input.AfterId)
+	}
+
+	// the items staying in place, and where the moved ones go between them
+	rest := make([]itemPosition, 0, len(current))
+	for _, p := range current {
+		if !movedIds[p.ItemId] {
+			rest = append(rest, p)
+		}
+	}
+	at := 0
+	if input.AfterId != nil {
+		for i, p := range rest {
+			if p.ItemId == *input.AfterId {
+				at = i + 1
+			}
+		}
+	}
+
+	var prev, next int64
+	if at > 0 {
+		prev = rest[at-1].Position
+	}
+	if at < len(rest) {
+		next = rest[at].Position
+	} else {
+		next = prev + positionGap*int64(len(input.ItemIds)+1)
+	}
+
+	step := (next - prev) / int64(len(input.ItemIds)+1)
+	if step > 0 {
+		moved := make([]itemPosition, len(input.ItemIds))
+		for i, id := range input.ItemIds {
+			moved[i] = itemPosition{ItemId: id, Position: prev + step*int64(i+1)}
+		}
+		return moved, nil
+	}
+
+	// no room left, renumber the whole list
+	order := make([]int, 0, len(current))
+	for _, p := range rest[:at] {
+		order = append(order, p.ItemId)
+	}
+	order = append(order, input.ItemIds...)
+	for _, p := range rest[at:] {
+		order = append(order, p.ItemId)
+	}
+	renumbered := make([]itemPosition, len(order))
+	for i, id := range order {
+		renumbered[i] = itemPosition{ItemId: id, Position: positionGap * int64(i+1)}
+	}
+	return renumbered, nil
+}
+
+// Update returns the id of the next occurrence of a repeating item it marks
+// done, or 0.
+func (r *TodoItemPostgres) Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	old, err := lockItem(ctx, tx, userId, itemId)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	nextId, err := updateItem(ctx, tx, userId, old, input)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return nextId, tx.Commit()
+}
+
+// resetReminders drops the pending reminders of an item whose due or remind
+// time changed, so they don't fire for the old time.
+func resetReminders(ctx context.Context, tx *sqlx.Tx, itemId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE item_id = $1 AND sent_at IS NULL", reminderDeliveriesTable)
+	if _, err := tx.ExecContext(ctx, query, itemId); err != nil {
+		return fmt.Errorf("failed to reset reminders: %w", err)
+	}
+	return nil
+}
+
+// updateItem applies the input to the item, which is locked by the caller.
+func updateItem(ctx context.Context, tx *sqlx.Tx, userId int, old todo.TodoItem, input todo.UpdateItemInput) (int, error) {
+	itemId := old.Id
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
+	changes := todo.Changes{}
 
 	if input.Title != nil {
 		setValues = append(setValues, fmt.Sprintf("title=$%d", argId))
 		args = append(args, *input.Title)
 		argId++
+		addChange(changes, "title", old.Title, *input.Title)
 	}
 
 	if input.Description != nil {
 		setValues = append(setValues, fmt.Sprintf("description=$%d", argId))
 		args = append(args, *input.Description)
 		argId++
+		addChange(changes, "description", old.Description, *input.Description)
 	}
 
 	if input.Done != nil {
 		setValues = append(setValues, fmt.Sprintf("done=$%d", argId))
 		args = append(args, *input.Done)
 		argId++
+		addChange(changes, "done", old.Done, *input.Done)
 	}
 
+	if input.AutoComplete != nil {
+		setValues = append(setValues, fmt.Sprintf("auto_complete=$%d", argId))
+		args = append(args, *input.AutoComplete)
+		argId++
+		addChange(changes, "auto_complete", old.AutoComplete, *input.AutoComplete)
+	}
+
+	if input.DueAt != nil {
+		setValues = append(setValues, fmt.Sprintf("due_at=$%d", argId))
+		args = append(args, *input.DueAt)
+		argId++
+		addChange(changes, "due_at", timeValue(old.DueAt), *input.DueAt)
+	} else if input.ClearDueAt {
+		setValues = append(setValues, "due_at=NULL")
+		addChange(changes, "due_at", timeValue(old.DueAt), nil)
+	}
+
+	if input.Priority != nil {
+		setValues = append(setValues, fmt.Sprintf("priority=$%d", argId))
+		args = append(args, *input.Priority)
+		argId++
+		addChange(changes, "priority", old.Priority, *input.Priority)
+	}
+
+	if input.RemindAt != nil {
+		setValues = append(setValues, fmt.Sprintf("remind_at=$%d", argId))
+		args = append(args, *input.RemindAt)
+		argId++
+		addChange(changes, "remind_at", timeValue(old.RemindAt), *input.RemindAt)
+	} else if input.ClearRemindAt {
+		setValues = append(setValues, "remind_at=NULL")
+		addChange(changes, "remind_at", timeValue(old.RemindAt), nil)
+	}
+
+	setValues = append(setValues, "updated_at=now()")
 	setQuery := strings.Join(setValues, ", ")
 
-	query := fmt.Sprintf(`UPDATE %s ti SET %s FROM %s li, %s ul
-									WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $%d AND ti.id = $%d`,
-		todoItemsTable, setQuery, listsItemsTable, usersListsTable, argId, argId+1)
-	args = append(args, userId, itemId)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d", todoItemsTable, setQuery, argId)
+	args = append(args, itemId)
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	_, dueChanged := changes["due_at"]
+	_, remindChanged := changes["remind_at"]
+	if dueChanged || remindChanged {
+		if err := resetReminders(ctx, tx, itemId); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(changes) > 0 {
+		action := todo.ActionItemUpdated
+		if input.Done != nil && *input.Done && !old.Done {
+			action = todo.ActionItemDone
+		}
+		err := logActivity(ctx, tx, todo.Activity{
+			ListId: old.ListId, ItemId: &itemId, UserId: &userId, Action: action, Changes: changes,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// an item that starts auto-completing follows its subtasks right away,
+	// and the parents follow the item
+	var syncFrom *int
+	if input.AutoComplete != nil && *input.AutoComplete && !old.AutoComplete {
+		syncFrom = &itemId
+	} else if input.Done != nil && *input.Done != old.Done {
+		syncFrom = old.ParentId
+	}
+	if err := syncCompletion(ctx, tx, userId, syncFrom); err != nil {
+		return 0, err
+	}
+
+	if old.SeriesId == nil || input.Done == nil || !*input.Done || old.Done {
+		return 0, nil
+	}
+	item, err := lockItem(ctx, tx, userId, itemId)
+	if err != nil {
+		return 0, err
+	}
+	return createOccurrence(ctx, tx, userId, item, time.Now().UTC())
+}
+
+// SetRecurrence makes the item repeat by the rule, or changes the rule of its
+// series, which applies to the occurrences still to come.
+func (r *TodoItemPostgres) SetRecurrence(ctx context.Context, userId, itemId int, rule todo.Recurrence) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	item, err := lockItem(ctx, tx, userId, itemId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rule = rule.Anchor(item.DueAt)
+
+	if item.SeriesId == nil {
+		seriesId, err := createSeries(ctx, tx, rule)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		query := fmt.Sprintf("UPDATE %s SET series_id = $1, updated_at = now() WHERE id = $2", todoItemsTable)
+		if _, err = tx.ExecContext(ctx, query, seriesId, itemId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	} else {
+		query := fmt.Sprintf("UPDATE %s SET rule = $1, updated_at = now() WHERE id = $2", itemSeriesTable)
+		if _, err = tx.ExecContext(ctx, query, rule, *item.SeriesId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = logActivity(ctx, tx, todo.Activity{
+		ListId: item.ListId, ItemId: &itemId, UserId: &userId, Action: todo.ActionItemUpdated,
+		Changes: todo.Changes{"recurrence": {From: item.Recurrence, To: rule}},
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// StopRecurrence ends the series of the item. Its occurrences stay as they
+// are, but no more are created.
+func (r *TodoItemPostgres) StopRecurrence(ctx context.Context, userId, itemId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	item, err := lockItem(ctx, tx, userId, itemId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if item.SeriesId == nil {
+		tx.Rollback()
+		return fmt.Errorf("%w: item does not repeat", todo.ErrValidation)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", itemSeriesTable)
+	if _, err = tx.ExecContext(ctx, query, *item.SeriesId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = logActivity(ctx, tx, todo.Activity{
+		ListId: item.ListId, ItemId: &itemId, UserId: &userId, Action: todo.ActionItemUpdated,
+		Changes: todo.Changes{"recurrence": {From: item.Recurrence, To: nil}},
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockItem returns an item the user can write to and locks it until the end
+// of the transaction.
+func lockItem(ctx context.Context, tx *sqlx.Tx, userId, itemId int) (todo.TodoItem, error) {
+	var item todo.TodoItem
+	query := fmt.Sprintf(`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id
+									WHERE ti.id = $1 AND ul.user_id = $2 AND %s AND ti.deleted_at IS NULL FOR UPDATE OF ti`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, writeAccess)
+	err := tx.GetContext(ctx, &item, query, itemId, userId)
+	return item, notFound(err, "item")
+}
+
+// Batch runs the operations on the items of a list in one transaction, which
+// inserts runs of creates together. In a partial batch every operation runs
+// in a savepoint instead, and a failed one is rolled back on its own and
+// reported in its result.
+func (r *TodoItemPostgres) Batch(ctx context.Context, userId, listId int, ops []todo.BatchOperation, partial bool) ([]todo.BatchResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	if err = lockList(ctx, tx, userId, listId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	results := make([]todo.BatchResult, len(ops))
+	for n := 0; n < len(ops); n++ {
+		if partial {
+			if results[n], err = runSavepoint(ctx, tx, userId, listId, ops[n]); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			continue
+		}
+
+		if ops[n].Op == todo.BatchCreate {
+			end := n + 1
+			for end < len(ops) && ops[end].Op == todo.BatchCreate {
+				end++
+			}
+			ids, err := createItems(ctx, tx, userId, listId, ops[n:end])
+			if err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("operations %d to %d: %w", n, end-1, err)
+			}
+			for k, id := range ids {
+				results[n+k] = todo.BatchResult{Op: todo.BatchCreate, ItemId: id}
+			}
+			n = end - 1
+			continue
+		}
+
+		if results[n], err = runOperation(ctx, tx, userId, listId, ops[n]); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("operation %d: %w", n, err)
+		}
+	}
+
+	return results, tx.Commit()
+}
+
+// runSavepoint runs an operation of a partial batch, which it validates, and
+// rolls back to the state before it when it fails. Only errors that abort the
+// whole batch are returned.
+func runSavepoint(ctx context.Context, tx *sqlx.Tx, userId, listId int, op todo.BatchOperation) (todo.BatchResult, error) {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT batch_operation"); err != nil {
+		return todo.BatchResult{}, err
+	}
+
+	var result todo.BatchResult
+	err := op.Validate()
+	if err == nil {
+		result, err = runOperation(ctx, tx, userId, listId, op)
+	}
+	if err != nil {
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_operation"); err != nil {
+			return todo.BatchResult{}, err
+		}
+		return todo.BatchResult{Op: op.Op, ItemId: op.ItemId, Err: err}, nil
+	}
+
+	_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_operation")
+	return result, err
+}
+
+func runOperation(ctx context.Context, tx *sqlx.Tx, userId, listId int, op todo.BatchOperation) (todo.BatchResult, error) {
+	result := todo.BatchResult{Op: op.Op, ItemId: op.ItemId}
+	switch op.Op {
+	case todo.BatchCreate:
+		itemId, err := createItem(ctx, tx, userId, listId, *op.Item)
+		result.ItemId = itemId
+		return result, err
+	case todo.BatchUpdate:
+		item, err := lockItem(ctx, tx, userId, op.ItemId)
+		if err != nil {
+			return result, err
+		}
+		if item.ListId != listId {
+			return result, fmt.Errorf("%w: item %d is not in list %d", todo.ErrValidation, op.ItemId, listId)
+		}
+		result.NextItemId, err = updateItem(ctx, tx, userId, item, *op.Update)
+		return result, err
+	default:
+		itemListId, err := markDeleted(ctx, tx, userId, op.ItemId, deleteItemQuery, deleteSubtreeQuery, todo.ActionItemDeleted)
+		if err == nil && itemListId != listId {
+			err = fmt.Errorf("%w: item %d is not in list %d", todo.ErrValidation, op.ItemId, listId)
+		}
+		return result, err
+	}
+}
+
+// reserveItemIds takes n ids from the sequence of the items. Inserting many
+// items with their ids known beforehand pairs them with the items, which the
+// ids returned by a multi-row insert can't, as their order is not guaranteed.
+func reserveItemIds(ctx context.Context, tx *sqlx.Tx, n int) ([]int, error) {
+	var ids []int
+	query := fmt.Sprintf("SELECT nextval(pg_get_serial_sequence('%s', 'id')) FROM generate_series(1, $1)", todoItemsTable)
+	if err := tx.SelectContext(ctx, &ids, query, n); err != nil {
+		return nil, err
+	}
+	if len(ids) != n {
+		return nil, fmt.Errorf("failed to reserve item ids: got %d for %d items", len(ids), n)
+	}
+	return ids, nil
+}
+
+// createItems inserts the items of create operations at the end of the list,
+// which is locked by the caller, with one statement per table.
+func createItems(ctx context.Context, tx *sqlx.Tx, userId, listId int, ops []todo.BatchOperation) ([]int, error) {
+	items := make([]todo.TodoItem, len(ops))
+	values := make([]string, len(ops))
+	args := make([]interface{}, 0, len(ops)*9)
+	for k, op := range ops {
+		item := *op.Item
+		if item.ParentId != nil {
+			if err := checkParent(ctx, tx, listId, *item.ParentId); err != nil {
+				return nil, err
+			}
+		}
+		item.SeriesId = nil
+		if item.Recurrence != nil {
+			rule := item.Recurrence.Anchor(item.DueAt)
+			seriesId, err := createSeries(ctx, tx, rule)
+			if err != nil {
+				return nil, err
+			}
+			item.SeriesId, item.Recurrence = &seriesId, &rule
+		}
+		items[k] = item
+
+	}
+
+	ids, err := reserveItemIds(ctx, tx, len(items))
+	if err != nil {
+		return nil, err
+	}
+	for k, item := range items {
+		n := len(args)
+		values[k] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9)
+		args = append(args, ids[k], item.ParentId, item.Title, item.Description, item.AutoComplete, item.DueAt, item.Priority,
+			item.RemindAt, item.SeriesId)
+	}
+
+	createItemsQuery := fmt.Sprintf(`INSERT INTO %s (id, parent_id, title, description, auto_complete, due_at, priority, remind_at, series_id)
+									VALUES %s`, todoItemsTable, strings.Join(values, ", "))
+	if _, err = tx.ExecContext(ctx, createItemsQuery, args...); err != nil {
+		return nil, err
+	}
+
+	var position int64
+	positionQuery := fmt.Sprintf("SELECT COALESCE(max(position), 0) FROM %s WHERE list_id = $1", listsItemsTable)
+	if err = tx.GetContext(ctx, &position, positionQuery, listId); err != nil {
+		return nil, err
+	}
+
+	args = []interface{}{listId}
+	activities := make([]todo.Activity, len(ids))
+	for k, id := range ids {
+		position += positionGap
+		values[k] = fmt.Sprintf("($1, $%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, id, position)
+
+		itemId := id
+		activities[k] = todo.Activity{
+			ListId: listId, ItemId: &itemId, UserId: &userId, Action: todo.ActionItemCreated, Changes: createdChanges(items[k]),
+		}
+	}
+	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id, position) VALUES %s",
+		listsItemsTable, strings.Join(values, ", "))
+	if _, err = tx.ExecContext(ctx, createListItemsQuery, args...); err != nil {
+		return nil, err
+	}
+
+	if err = logActivities(ctx, tx, activities); err != nil {
+		return nil, err
+	}
+
+	// new subtasks reopen their auto-completed parents
+	synced := make(map[int]bool)
+	for _, item := range items {
+		if item.ParentId != nil && !synced[*item.ParentId] {
+			synced[*item.ParentId] = true
+			if err := syncCompletion(ctx, tx, userId, item.ParentId); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ids, nil
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	todo "github.com/LittleMikle/ToDo_List"
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
 	"testing"
+	"time"
 )
 
 func TestTodoItemPostgres_Create(t *testing.T) {
@@ -20,6 +22,7 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 	r := NewTodoItemPostgres(db)
 
 	type args struct {
+		userId int
 		listId int
 		item   todo.TodoItem
 	}
@@ -36,10 +39,75 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 		{
 			name: "OK",
 			args: args{
+				userId: 1,
 				listId: 1,
 				item: todo.TodoItem{
 					Title:       "test tittle",
 					Description: "test description",
+					DueAt:       timePointer(time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)),
+					Priority:    todo.PriorityHigh,
+				},
+			},
+			id: 2,
+			mockBehavior: func(args args, id int) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectQuery("INSERT INTO todo_items").
+					WithArgs(args.item.ParentId, args.item.Title, args.item.Description, args.item.AutoComplete, args.item.DueAt,
+						args.item.Priority, args.item.RemindAt, args.item.SeriesId).WillReturnRows(rows)
+
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(id, args.listId, args.userId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(args.listId, id, args.userId, todo.ActionItemCreated, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK Repeating",
+			args: args{
+				userId: 1,
+				listId: 1,
+				item: todo.TodoItem{
+					Title:      "water plants",
+					Recurrence: &todo.Recurrence{Freq: todo.RepeatWeekly, Weekdays: []string{"MO", "TH"}},
+				},
+			},
+			id: 2,
+			mockBehavior: func(args args, id int) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("INSERT INTO item_series").WithArgs(`{"freq":"weekly","weekdays":["MO","TH"]}`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectQuery("INSERT INTO todo_items").
+					WithArgs(nil, args.item.Title, "", false, nil, 0, nil, 4).WillReturnRows(rows)
+
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(id, args.listId, args.userId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(args.listId, id, args.userId, todo.ActionItemCreated,
+						`{"description":{"from":null,"to":""},"priority":{"from":null,"to":0},`+
+							`"recurrence":{"from":null,"to":{"freq":"weekly","weekdays":["MO","TH"]}},"title":{"from":null,"to":"water plants"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK Supplied Series Dropped",
+			args: args{
+				userId: 1,
+				listId: 1,
+				item: todo.TodoItem{
+					Title:    "water plants",
+					SeriesId: intPointer(9),
 				},
 			},
 			id: 2,
@@ -48,9 +116,13 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO todo_items").
-					WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+					WithArgs(nil, args.item.Title, "", false, nil, 0, nil, nil).WillReturnRows(rows)
+
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(id, args.listId, args.userId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
 
-				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(args.listId, id, args.userId, todo.ActionItemCreated, sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 				mock.ExpectCommit()
@@ -59,6 +131,7 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 		{
 			name: "Empty field",
 			args: args{
+				userId: 1,
 				listId: 1,
 				item: todo.TodoItem{
 					Title:       "",
@@ -71,7 +144,8 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id).RowError(1, errors.New("empty field error"))
 				mock.ExpectQuery("INSERT INTO todo_items").
-					WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+					WithArgs(args.item.ParentId, args.item.Title, args.item.Description, args.item.AutoComplete, args.item.DueAt,
+						args.item.Priority, args.item.RemindAt, args.item.SeriesId).WillReturnRows(rows)
 
 				mock.ExpectRollback()
 			},
@@ -80,6 +154,7 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 		{
 			name: "2 Insert Error",
 			args: args{
+				userId: 1,
 				listId: 1,
 				item: todo.TodoItem{
 					Title:       "test tittle",
@@ -92,22 +167,49 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO todo_items").
-					WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+					WithArgs(args.item.ParentId, args.item.Title, args.item.Description, args.item.AutoComplete, args.item.DueAt,
+						args.item.Priority, args.item.RemindAt, args.item.SeriesId).WillReturnRows(rows)
 
-				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(id, args.listId, args.userId).
 					WillReturnError(errors.New("error with 2 insert"))
 
 				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
+		{
+			name: "Read Only List",
+			args: args{
+				userId: 2,
+				listId: 1,
+				item: todo.TodoItem{
+					Title:       "test tittle",
+					Description: "test description",
+				},
+			},
+			id: 2,
+			mockBehavior: func(args args, id int) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectQuery("INSERT INTO todo_items").
+					WithArgs(args.item.ParentId, args.item.Title, args.item.Description, args.item.AutoComplete, args.item.DueAt,
+						args.item.Priority, args.item.RemindAt, args.item.SeriesId).WillReturnRows(rows)
+
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(id, args.listId, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior(testCase.args, testCase.id)
 
-			got, err := r.Create(testCase.args.listId, testCase.args.item)
+			got, err := r.Create(context.Background(), testCase.args.userId, testCase.args.listId, testCase.args.item)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -130,56 +232,267 @@ func TestTodoItemPostgres_GetAll(t *testing.T) {
 	type args struct {
 		listId int
 		userId int
+		query  todo.ListQuery
 	}
 
+	defaultQuery := todo.ListQuery{Limit: 50, Sort: "id", Order: "asc"}
+	done := false
+
 	testTable := []struct {
-		name    string
-		mock    func()
-		input   args
-		want    []todo.TodoItem
-		wantErr bool
+		name     string
+		mock     func()
+		input    args
+		want     []todo.TodoItem
+		wantPage todo.PageInfo
+		wantErr  bool
 	}{
 		{
 			name: "OK",
 			mock: func() {
+				mock.ExpectQuery("SELECT count(.+) FROM todo_items ti INNER JOIN lists_items li on (.+)").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
 				rows := sqlmock.NewRows([]string{"id", "title", "description", "done"}).
 					AddRow(1, "title1", "description1", true).
 					AddRow(2, "title2", "description2", false).
 					AddRow(3, "title3", "description3", false)
 
-				mock.ExpectQuery("SELECT (.+) FROM todo_items ti INNER JOIN lists_items li on (.+)").
-					WithArgs(1, 1).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti INNER JOIN lists_items li on (.+) ORDER BY ti.id ASC LIMIT (.+)").
+					WithArgs(1, 1, 51).WillReturnRows(rows)
 			},
 			input: args{
 				listId: 1,
 				userId: 1,
+				query:  defaultQuery,
 			},
 			want: []todo.TodoItem{
-				{1, "title1", "description1", true},
-				{2, "title2", "description2", false},
-				{3, "title3", "description3", false},
+				{Id: 1, Title: "title1", Description: "description1", Done: true},
+				{Id: 2, Title: "title2", Description: "description2", Done: false},
+				{Id: 3, Title: "title3", Description: "description3", Done: false},
 			},
+			wantPage: todo.PageInfo{Total: 3},
 		},
 		{
 			name: "NO Records",
 			mock: func() {
+				mock.ExpectQuery("SELECT count(.+) FROM todo_items ti INNER JOIN lists_items li on (.+)").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
 				rows := sqlmock.NewRows([]string{"id", "title", "description", "done"})
 
 				mock.ExpectQuery("SELECT (.+) FROM todo_items ti INNER JOIN lists_items li on (.+)").
-					WithArgs(1, 1).WillReturnRows(rows)
+					WithArgs(1, 1, 51).WillReturnRows(rows)
+			},
+			input: args{
+				listId: 1,
+				userId: 1,
+				query:  defaultQuery,
+			},
+		},
+		{
+			name: "Filtered Next Page",
+			mock: func() {
+				mock.ExpectQuery("SELECT count(.+) FROM todo_items ti INNER JOIN lists_items li on (.+) AND ti.title ILIKE (.+) AND ti.done = (.+)").
+					WithArgs(1, 1, "%50\\%%", false).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done"}).
+					AddRow(4, "b", "", false).
+					AddRow(3, "a", "", false)
+
+				mock.ExpectQuery("SELECT (.+) WHERE (.+) AND \\(ti.title, ti.id\\) < (.+) ORDER BY ti.title DESC, ti.id DESC LIMIT (.+)").
+					WithArgs(1, 1, "%50\\%%", false, "c", 5, 2).WillReturnRows(rows)
+			},
+			input: args{
+				listId: 1,
+				userId: 1,
+				query: todo.ListQuery{
+					Limit:  1,
+					Cursor: encodeCursor("title", 5, "c"),
+					Sort:   "title",
+					Order:  "desc",
+					Title:  "50%",
+					Done:   &done,
+				},
+			},
+			want: []todo.TodoItem{
+				{Id: 4, Title: "b", Done: false},
+			},
+			wantPage: todo.PageInfo{
+				NextCursor: encodeCursor("title", 4, "b"),
+				Total:      5,
+			},
+		},
+		{
+			name: "Cursor Of Other Sort",
+			mock: func() {
+				mock.ExpectQuery("SELECT count(.+) FROM todo_items ti INNER JOIN lists_items li on (.+)").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 			},
 			input: args{
 				listId: 1,
 				userId: 1,
+				query: todo.ListQuery{
+					Limit:  1,
+					Cursor: encodeCursor("id", 5, ""),
+					Sort:   "title",
+					Order:  "asc",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, page, err := r.GetAll(context.Background(), testCase.input.userId, testCase.input.listId, testCase.input.query)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+				assert.Equal(t, testCase.wantPage, page)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetDue(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with GetDue conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	now := time.Date(2023, 7, 12, 10, 0, 0, 0, time.UTC)
+	weekEnd := time.Date(2023, 7, 17, 0, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2023, 7, 14, 18, 0, 0, 0, time.UTC)
+
+	type args struct {
+		from   *time.Time
+		before time.Time
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   args
+		want    []todo.TodoItem
+		wantErr bool
+	}{
+		{
+			name: "Overdue",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done", "due_at", "priority"}).
+					AddRow(1, "title1", "description1", false, now.Add(-time.Hour), 3)
+
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) WHERE ul.user_id = (.+) AND ti.done = false AND ti.due_at < (.+) ORDER BY (.+)").
+					WithArgs(1, now).WillReturnRows(rows)
+			},
+			input: args{before: now},
+			want: []todo.TodoItem{
+				{Id: 1, Title: "title1", Description: "description1", DueAt: timePointer(now.Add(-time.Hour)), Priority: 3},
+			},
+		},
+		{
+			name: "Due This Week",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done", "due_at", "priority"}).
+					AddRow(2, "title2", "description2", false, dueAt, 0)
+
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) AND ti.due_at < (.+) AND ti.due_at >= (.+) ORDER BY (.+)").
+					WithArgs(1, weekEnd, now).WillReturnRows(rows)
+			},
+			input: args{from: &now, before: weekEnd},
+			want: []todo.TodoItem{
+				{Id: 2, Title: "title2", Description: "description2", DueAt: &dueAt},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetDue(context.Background(), 1, testCase.input.from, testCase.input.before)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetDated(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with GetDated conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	dueAt := time.Date(2023, 7, 14, 18, 0, 0, 0, time.UTC)
+	listId := 2
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		listId  *int
+		want    []todo.TodoItem
+		wantErr bool
+	}{
+		{
+			name: "All Lists",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "done", "due_at", "list_id"}).
+					AddRow(1, "title1", true, dueAt, 1).
+					AddRow(2, "title2", false, dueAt, 2)
+
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) WHERE ul.user_id = (.+) AND ti.due_at IS NOT NULL AND ti.deleted_at IS NULL AND tl.deleted_at IS NULL ORDER BY ti.due_at, ti.id").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			want: []todo.TodoItem{
+				{Id: 1, Title: "title1", Done: true, DueAt: &dueAt, ListId: 1},
+				{Id: 2, Title: "title2", DueAt: &dueAt, ListId: 2},
 			},
 		},
+		{
+			name: "One List",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "done", "due_at", "list_id"}).
+					AddRow(2, "title2", false, dueAt, 2)
+
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) AND tl.deleted_at IS NULL AND li.list_id = (.+) ORDER BY (.+)").
+					WithArgs(1, listId).WillReturnRows(rows)
+			},
+			listId: &listId,
+			want: []todo.TodoItem{
+				{Id: 2, Title: "title2", DueAt: &dueAt, ListId: 2},
+			},
+		},
+		{
+			name: "Failed",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+)").
+					WithArgs(1).WillReturnError(errors.New("some error"))
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.GetAll(testCase.input.userId, testCase.input.listId)
+			got, err := r.GetDated(context.Background(), 1, testCase.listId)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -225,7 +538,7 @@ func TestTodoItemPostgres_GetById(t *testing.T) {
 				itemId: 1,
 				userId: 1,
 			},
-			want: todo.TodoItem{1, "title1", "description1", true},
+			want: todo.TodoItem{Id: 1, Title: "title1", Description: "description1", Done: true},
 		},
 		{
 			name: "Not Found",
@@ -247,7 +560,7 @@ func TestTodoItemPostgres_GetById(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.GetById(testCase.input.userId, testCase.input.itemId)
+			got, err := r.GetById(context.Background(), testCase.input.userId, testCase.input.itemId)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -267,6 +580,7 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 	defer db.Close()
 
 	r := NewTodoItemPostgres(db)
+	deletedAt := time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)
 
 	type args struct {
 		itemId int
@@ -274,16 +588,23 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 	}
 
 	testTable := []struct {
-		name    string
-		mock    func()
-		input   args
-		wantErr bool
+		name      string
+		mock      func()
+		input     args
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				mock.ExpectExec("DELETE FROM todo_items ti USING lists_items li, users_lists ul WHERE (.+)").
-					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE todo_items ti SET deleted_at = now\\(\\) FROM lists_items li, users_lists ul WHERE (.+) RETURNING li.list_id").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"list_id", "parent_id", "deleted_at"}).AddRow(3, nil, deletedAt))
+				mock.ExpectExec("WITH RECURSIVE subtree AS (.+) UPDATE todo_items ti SET deleted_at = \\$2 FROM subtree s").
+					WithArgs(1, deletedAt).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemDeleted, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			input: args{
 				itemId: 1,
@@ -293,13 +614,30 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 		{
 			name: "NOT FOUND",
 			mock: func() {
-				mock.ExpectExec("DELETE FROM todo_items ti USING lists_items li, users_lists ul WHERE (.+)").
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE todo_items ti SET deleted_at = now\\(\\) FROM lists_items li, users_lists ul WHERE (.+)").
 					WithArgs(1, 404).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
 			},
 			input: args{
 				itemId: 404,
 				userId: 1,
 			},
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+		{
+			name: "DB Error",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE todo_items ti SET deleted_at = now\\(\\) FROM lists_items li, users_lists ul WHERE (.+)").
+					WithArgs(1, 1).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+			},
 			wantErr: true,
 		},
 	}
@@ -308,9 +646,12 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			err := r.Delete(testCase.input.userId, testCase.input.itemId)
+			err := r.Delete(context.Background(), testCase.input.userId, testCase.input.itemId)
 			if testCase.wantErr {
 				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -319,6 +660,36 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 	}
 }
 
+func TestTodoItemPostgres_Restore(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with Restore conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+	deletedAt := time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE todo_items ti SET deleted_at = NULL FROM lists_items li, users_lists ul, todo_lists tl, todo_items prev WHERE (.+) RETURNING li.list_id").
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"list_id", "parent_id", "deleted_at"}).AddRow(3, nil, deletedAt))
+	mock.ExpectExec("WITH RECURSIVE subtree AS (.+) UPDATE todo_items ti SET deleted_at = NULL FROM subtree s (.+) AND ti.deleted_at = \\$2").
+		WithArgs(1, deletedAt).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO activity").
+		WithArgs(3, 1, 1, todo.ActionItemRestored, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.Restore(context.Background(), 1, 1))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE todo_items ti SET deleted_at = NULL FROM lists_items li, users_lists ul, todo_lists tl, todo_items prev WHERE (.+)").
+		WithArgs(1, 404).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.Restore(context.Background(), 1, 404), todo.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestNewTodoItemPostgres_Update(t *testing.T) {
 	db, mock, err := sqlmock.Newx()
 	if err != nil {
@@ -334,17 +705,41 @@ func TestNewTodoItemPostgres_Update(t *testing.T) {
 		input  todo.UpdateItemInput
 	}
 
+	oldItem := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "title", "description", "done", "due_at", "priority", "remind_at",
+			"created_at", "updated_at", "deleted_at", "list_id"}).
+			AddRow(1, "old title", "old description", false, nil, todo.PriorityLow, nil,
+				time.Time{}, time.Time{}, nil, 3)
+	}
+	repeatingItem := func(done bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "title", "description", "done", "due_at", "priority", "remind_at",
+			"series_id", "recurrence", "created_at", "updated_at", "deleted_at", "list_id"}).
+			AddRow(1, "water plants", "", done, time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC), todo.PriorityLow,
+				time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC), 5, []byte(`{"freq":"daily","interval":2}`),
+				time.Time{}, time.Time{}, nil, 3)
+	}
+
 	testTable := []struct {
-		name    string
-		mock    func()
-		input   args
-		wantErr bool
+		name      string
+		mock      func()
+		input     args
+		want      int
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "OK ALL",
 			mock: func() {
-				mock.ExpectExec("UPDATE todo_items ti SET (.+) FROM lists_items li, users_lists ul WHERE (.+)").
-					WithArgs("new title", "new description", true, 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(oldItem())
+				mock.ExpectExec("UPDATE todo_items SET (.+) WHERE id=(.+)").
+					WithArgs("new title", "new description", true, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemDone, `{"description":{"from":"old description","to":"new description"},`+
+						`"done":{"from":false,"to":true},"title":{"from":"old title","to":"new title"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			input: args{
 				itemId: 1,
@@ -357,41 +752,173 @@ func TestNewTodoItemPostgres_Update(t *testing.T) {
 			},
 		},
 		{
-			name: "OK WITHOUT NO DONE AND DESCRIPTION",
+			name: "OK SCHEDULE",
 			mock: func() {
-				mock.ExpectExec("UPDATE todo_items ti SET (.+) FROM lists_items li, users_lists ul WHERE (.+)").
-					WithArgs("new title", 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(oldItem())
+				mock.ExpectExec("UPDATE todo_items SET due_at=(.+), priority=(.+), remind_at=(.+) WHERE id=(.+)").
+					WithArgs(time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC), 2, time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM reminder_deliveries WHERE item_id = \\$1 AND sent_at IS NULL").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemUpdated, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			input: args{
 				itemId: 1,
 				userId: 1,
 				input: todo.UpdateItemInput{
-					Title: stringPointer("new title"),
+					DueAt:    timePointer(time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)),
+					Priority: intPointer(todo.PriorityMedium),
+					RemindAt: timePointer(time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC)),
 				},
 			},
 		},
 		{
-			name: "OK_NoInputFields",
+			name: "OK Clear Schedule",
 			mock: func() {
-				mock.ExpectExec("UPDATE todo_items ti SET FROM lists_items li, users_lists ul WHERE (.+)").
-					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(repeatingItem(false))
+				mock.ExpectExec("UPDATE todo_items SET due_at=NULL, remind_at=NULL, updated_at=now\\(\\) WHERE id=\\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM reminder_deliveries WHERE item_id = \\$1 AND sent_at IS NULL").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemUpdated, `{"due_at":{"from":"2023-07-10T09:00:00Z","to":null},`+
+						`"remind_at":{"from":"2023-07-10T08:00:00Z","to":null}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			input: args{
 				itemId: 1,
 				userId: 1,
+				input:  todo.UpdateItemInput{ClearDueAt: true, ClearRemindAt: true},
 			},
 		},
-	}
-
-	for _, testCase := range testTable {
-		t.Run(testCase.name, func(t *testing.T) {
-			testCase.mock()
+		{
+			name: "OK UNCHANGED",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(oldItem())
+				mock.ExpectExec("UPDATE todo_items SET (.+) WHERE id=(.+)").
+					WithArgs("old title", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				input: todo.UpdateItemInput{
+					Title: stringPointer("old title"),
+				},
+			},
+		},
+		{
+			name: "OK_NoInputFields",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(oldItem())
+				mock.ExpectExec("UPDATE todo_items SET updated_at=now\\(\\) WHERE id=(.+)").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+			},
+		},
+		{
+			name: "OK Next Occurrence",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(repeatingItem(false))
+				mock.ExpectExec("UPDATE todo_items SET done=(.+) WHERE id=(.+)").
+					WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemDone, `{"done":{"from":false,"to":true}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(repeatingItem(true))
+				mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM todo_items WHERE series_id = (.+)\\)").
+					WithArgs(5, 1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery("INSERT INTO todo_items").
+					WithArgs(nil, "water plants", "", false, sqlmock.AnyArg(), todo.PriorityLow, sqlmock.AnyArg(), 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(3, 7).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO items_labels (.+) SELECT (.+) FROM items_labels").WithArgs(7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 7, 1, todo.ActionItemCreated, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				input:  todo.UpdateItemInput{Done: boolPointer(true)},
+			},
+			want: 7,
+		},
+		{
+			name: "OK Open Occurrence",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(repeatingItem(false))
+				mock.ExpectExec("UPDATE todo_items SET done=(.+) WHERE id=(.+)").
+					WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemDone, `{"done":{"from":false,"to":true}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(repeatingItem(true))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(5, 1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectCommit()
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				input:  todo.UpdateItemInput{Done: boolPointer(true)},
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(404, 1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			input: args{
+				itemId: 404,
+				userId: 1,
+				input: todo.UpdateItemInput{
+					Title: stringPointer("new title"),
+				},
+			},
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
 
-			err = r.Update(testCase.input.userId, testCase.input.itemId, testCase.input.input)
+			got, err := r.Update(context.Background(), testCase.input.userId, testCase.input.itemId, testCase.input.input)
 			if testCase.wantErr {
 				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -405,3 +932,738 @@ func stringPointer(s string) *string {
 func boolPointer(b bool) *bool {
 	return &b
 }
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func timePointer(t time.Time) *time.Time {
+	return &t
+}
+
+func TestTodoItemPostgres_Reorder(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with Reorder conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	positions := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"item_id", "position"}).
+			AddRow(1, 1024).AddRow(2, 2048).AddRow(3, 3072)
+	}
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		input     todo.ReorderItemsInput
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl INNER JOIN users_lists ul on (.+) FOR UPDATE OF tl").
+					WithArgs(1, 5).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectQuery("SELECT li.item_id, li.position FROM lists_items li (.+) ORDER BY li.position, li.item_id").
+					WithArgs(5).WillReturnRows(positions())
+				mock.ExpectExec("UPDATE lists_items li SET position = u.position FROM unnest(.+)").
+					WithArgs(5, "{3}", "{1536}").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(5, nil, 1, todo.ActionItemsReordered, `{"item_ids":{"from":null,"to":[3]}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			input: todo.ReorderItemsInput{ItemIds: []int{3}, AfterId: intPointer(1)},
+		},
+		{
+			name: "List Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+)").
+					WithArgs(1, 5).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			input:     todo.ReorderItemsInput{ItemIds: []int{3}},
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+		{
+			name: "Item Not In List",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+)").
+					WithArgs(1, 5).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectQuery("SELECT li.item_id, li.position FROM lists_items li (.+)").
+					WithArgs(5).WillReturnRows(positions())
+				mock.ExpectRollback()
+			},
+			input:     todo.ReorderItemsInput{ItemIds: []int{9}},
+			wantErr:   true,
+			wantErrIs: todo.ErrValidation,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Reorder(context.Background(), 1, 5, testCase.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReorderPositions(t *testing.T) {
+	current := []itemPosition{{1, 1024}, {2, 2048}, {3, 3072}}
+
+	testTable := []struct {
+		name    string
+		current []itemPosition
+		input   todo.ReorderItemsInput
+		want    []itemPosition
+		wantErr bool
+	}{
+		{
+			name:    "To Top",
+			current: current,
+			input:   todo.ReorderItemsInput{ItemIds: []int{3}},
+			want:    []itemPosition{{3, 512}},
+		},
+		{
+			name:    "Between Neighbours",
+			current: current,
+			input:   todo.ReorderItemsInput{ItemIds: []int{3}, AfterId: intPointer(1)},
+			want:    []itemPosition{{3, 1536}},
+		},
+		{
+			name:    "Several To End",
+			current: current,
+			input:   todo.ReorderItemsInput{ItemIds: []int{3, 1}, AfterId: intPointer(2)},
+			want:    []itemPosition{{3, 3072}, {1, 4096}},
+		},
+		{
+			name:    "Renumber Without Gap",
+			current: []itemPosition{{1, 1}, {2, 2}, {3, 3}},
+			input:   todo.ReorderItemsInput{ItemIds: []int{3}, AfterId: intPointer(1)},
+			want:    []itemPosition{{1, 1024}, {3, 2048}, {2, 3072}},
+		},
+		{
+			name:    "Unknown Item",
+			current: current,
+			input:   todo.ReorderItemsInput{ItemIds: []int{4}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown After",
+			current: current,
+			input:   todo.ReorderItemsInput{ItemIds: []int{1}, AfterId: intPointer(4)},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := reorderPositions(testCase.current, testCase.input)
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, todo.ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestTodoItemPostgres_Move(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with Move conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	item := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "title", "list_id", "position"}).AddRow(1, "title", 3, 1024)
+	}
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		listId    int
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(item())
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+) FOR UPDATE OF tl").
+					WithArgs(1, 5).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectExec("WITH RECURSIVE subtree AS (.+) UPDATE lists_items li SET list_id = \\$2, position = (.+) WHERE li.item_id = moved.item_id").
+					WithArgs(1, 5).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemMoved, `{"list_id":{"from":3,"to":5}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(5, 1, 1, todo.ActionItemMoved, `{"list_id":{"from":3,"to":5}}`).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+			listId: 5,
+		},
+		{
+			name: "Item Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+)").
+					WithArgs(1, 1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			listId:    5,
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+		{
+			name: "Same List",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+)").
+					WithArgs(1, 1).WillReturnRows(item())
+				mock.ExpectRollback()
+			},
+			listId:    3,
+			wantErr:   true,
+			wantErrIs: todo.ErrValidation,
+		},
+		{
+			name: "Target List Not Writable",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+)").
+					WithArgs(1, 1).WillReturnRows(item())
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+)").
+					WithArgs(1, 5).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			listId:    5,
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Move(context.Background(), 1, 1, testCase.listId)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_Copy(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with Copy conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) WHERE ti.id = \\$1 AND ul.user_id = \\$2 AND ti.deleted_at IS NULL").
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "done", "priority", "list_id"}).
+		AddRow(1, "title", "", true, todo.PriorityHigh, 3))
+	mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+) FOR UPDATE OF tl").
+		WithArgs(1, 5).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectQuery("INSERT INTO todo_items").
+		WithArgs("title", "", true, nil, todo.PriorityHigh, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO lists_items").
+		WithArgs(5, 7).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO activity").
+		WithArgs(5, 7, 1, todo.ActionItemCreated, `{"copied_from":{"from":null,"to":1},"description":{"from":null,"to":""},"done":{"from":false,"to":true},`+
+			`"priority":{"from":null,"to":3},"title":{"from":null,"to":"title"}}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	id, err := r.Copy(context.Background(), 1, 1, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+)").
+		WithArgs(404, 1).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = r.Copy(context.Background(), 1, 404, 5)
+	assert.ErrorIs(t, err, todo.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTodoItemPostgres_CreateSubtask(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with CreateSubtask conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+	item := todo.TodoItem{ParentId: intPointer(4), Title: "subtask"}
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("WITH RECURSIVE ancestors AS (.+) SELECT li.list_id, (.+) AS depth").
+					WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"list_id", "depth"}).AddRow(1, 1))
+				mock.ExpectQuery("INSERT INTO todo_items").
+					WithArgs(item.ParentId, "subtask", "", false, nil, 0, nil, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(5, 1, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(1, 5, 1, todo.ActionItemCreated, `{"description":{"from":null,"to":""},"parent_id":{"from":null,"to":4},`+
+						`"priority":{"from":null,"to":0},"title":{"from":null,"to":"subtask"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT ti.id, ti.parent_id, ti.done, ti.auto_complete, li.list_id, (.+) FOR UPDATE OF ti").
+					WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "done", "auto_complete", "list_id", "children_done"}).
+					AddRow(4, nil, true, false, 1, false))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Parent In Another List",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("WITH RECURSIVE ancestors AS (.+)").
+					WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"list_id", "depth"}).AddRow(2, 1))
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			wantErrIs: todo.ErrValidation,
+		},
+		{
+			name: "Parent Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("WITH RECURSIVE ancestors AS (.+)").
+					WithArgs(4).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			wantErrIs: todo.ErrValidation,
+		},
+		{
+			name: "Too Deep",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("WITH RECURSIVE ancestors AS (.+)").
+					WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"list_id", "depth"}).AddRow(1, todo.MaxItemDepth))
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			wantErrIs: todo.ErrValidation,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			id, err := r.Create(context.Background(), 1, 1, item)
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, testCase.wantErrIs)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 5, id)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetTree(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with GetTree conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	rows := sqlmock.NewRows([]string{"id", "parent_id", "title", "list_id"}).
+		AddRow(1, nil, "parent", 3).AddRow(2, 1, "child", 3)
+	mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) WHERE li.list_id = \\$1 AND ul.user_id = \\$2 AND ti.deleted_at IS NULL ORDER BY li.position, ti.id").
+		WithArgs(3, 1).WillReturnRows(rows)
+
+	items, err := r.GetTree(context.Background(), 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.TodoItem{
+		{Id: 1, Title: "parent", ListId: 3},
+		{Id: 2, ParentId: intPointer(1), Title: "child", ListId: 3},
+	}, items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncCompletion(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with syncCompletion conn to db")
+	}
+	defer db.Close()
+
+	columns := []string{"id", "parent_id", "done", "auto_complete", "list_id", "children_done"}
+
+	mock.ExpectBegin()
+	// the parent completes, the grandparent still has undone subtasks
+	mock.ExpectQuery("SELECT ti.id, ti.parent_id, (.+) FOR UPDATE OF ti").
+		WithArgs(2).WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 1, false, true, 3, true))
+	mock.ExpectExec("UPDATE todo_items SET done = \\$1, updated_at = now\\(\\) WHERE id = \\$2").
+		WithArgs(true, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO activity").
+		WithArgs(3, 2, 7, todo.ActionItemDone, `{"done":{"from":false,"to":true}}`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT ti.id, ti.parent_id, (.+) FOR UPDATE OF ti").
+		WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, nil, false, true, 3, false))
+	mock.ExpectCommit()
+
+	tx, err := db.Beginx()
+	assert.NoError(t, err)
+	assert.NoError(t, syncCompletion(context.Background(), tx, 7, intPointer(2)))
+	assert.NoError(t, syncCompletion(context.Background(), tx, 7, nil))
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTodoItemPostgres_GetAllForUser(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with GetAllForUser conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+	query := todo.ListQuery{Limit: 1, Sort: "id", Order: "asc", Labels: []string{"home", "urgent"}}
+
+	mock.ExpectQuery("SELECT count(.+) FROM todo_items ti (.+) INNER JOIN todo_lists tl on (.+) WHERE ul.user_id = \\$1 (.+) "+
+		"AND ti.id IN \\(SELECT il.item_id FROM items_labels il (.+) WHERE lb.user_id = \\$1 AND lb.name = ANY\\(\\$2\\) "+
+		"GROUP BY il.item_id HAVING count\\(\\*\\) = \\$3\\)").
+		WithArgs(1, "{\"home\",\"urgent\"}", 2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) ORDER BY ti.id ASC LIMIT \\$4").
+		WithArgs(1, "{\"home\",\"urgent\"}", 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "list_id"}).AddRow(4, "title4", 1).AddRow(9, "title9", 2))
+
+	items, page, err := r.GetAllForUser(context.Background(), 1, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.TodoItem{{Id: 4, Title: "title4", ListId: 1}}, items)
+	assert.Equal(t, todo.PageInfo{Total: 2, NextCursor: encodeCursor("id", 4, "")}, page)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTodoItemPostgres_SetRecurrence(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with SetRecurrence conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	rule := todo.Recurrence{Freq: todo.RepeatMonthly, MonthDay: 31}
+	item := func(seriesId interface{}, recurrence interface{}) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "title", "series_id", "recurrence", "list_id"}).
+			AddRow(1, "pay rent", seriesId, recurrence, 3)
+	}
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		rule      todo.Recurrence
+		wantErrIs error
+	}{
+		{
+			name: "OK New Series",
+			rule: rule,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(item(nil, nil))
+				mock.ExpectQuery("INSERT INTO item_series").WithArgs(`{"freq":"monthly","month_day":31}`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectExec("UPDATE todo_items SET series_id = (.+) WHERE id = (.+)").
+					WithArgs(5, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemUpdated, `{"recurrence":{"from":null,"to":{"freq":"monthly","month_day":31}}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK Change Series",
+			rule: rule,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(item(5, []byte(`{"freq":"monthly"}`)))
+				mock.ExpectExec("UPDATE item_series SET rule = (.+) WHERE id = (.+)").
+					WithArgs(`{"freq":"monthly","month_day":31}`, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemUpdated,
+						`{"recurrence":{"from":{"freq":"monthly"},"to":{"freq":"monthly","month_day":31}}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK Anchored To Due Day",
+			rule: todo.Recurrence{Freq: todo.RepeatMonthly},
+			mock: func() {
+				dueAt := time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "due_at", "series_id", "recurrence", "list_id"}).
+					AddRow(1, "pay rent", dueAt, 5, []byte(`{"freq":"weekly"}`), 3))
+				mock.ExpectExec("UPDATE item_series SET rule = (.+) WHERE id = (.+)").
+					WithArgs(`{"freq":"monthly","month_day":31}`, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemUpdated,
+						`{"recurrence":{"from":{"freq":"weekly"},"to":{"freq":"monthly","month_day":31}}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not Found",
+			rule: rule,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").
+					WithArgs(1, 1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErrIs: todo.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.SetRecurrence(context.Background(), 1, 1, testCase.rule)
+			if testCase.wantErrIs != nil {
+				assert.ErrorIs(t, err, testCase.wantErrIs)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_StopRecurrence(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with StopRecurrence conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		wantErrIs error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "series_id", "recurrence", "list_id"}).
+						AddRow(1, 5, []byte(`{"freq":"daily"}`), 3))
+				mock.ExpectExec("DELETE FROM item_series WHERE id = (.+)").WithArgs(5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 1, 1, todo.ActionItemUpdated, `{"recurrence":{"from":{"freq":"daily"},"to":null}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not Repeating",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "series_id", "recurrence", "list_id"}).AddRow(1, nil, nil, 3))
+				mock.ExpectRollback()
+			},
+			wantErrIs: todo.ErrValidation,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.StopRecurrence(context.Background(), 1, 1)
+			if testCase.wantErrIs != nil {
+				assert.ErrorIs(t, err, testCase.wantErrIs)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_Batch(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with Batch conn to db")
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	deletedAt := time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)
+	ops := []todo.BatchOperation{
+		{Op: todo.BatchCreate, Item: &todo.TodoItem{Title: "first"}},
+		{Op: todo.BatchCreate, Item: &todo.TodoItem{Title: "second", Priority: todo.PriorityHigh}},
+		{Op: todo.BatchDelete, ItemId: 4},
+	}
+
+	testTable := []struct {
+		name      string
+		ops       []todo.BatchOperation
+		partial   bool
+		mock      func()
+		want      []todo.BatchResult
+		wantErrIs error
+	}{
+		{
+			name: "OK",
+			ops:  ops,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+) FOR UPDATE OF tl").WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery("SELECT nextval\\(pg_get_serial_sequence\\('todo_items', 'id'\\)\\) FROM generate_series\\(1, \\$1\\)").
+					WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(7).AddRow(8))
+				mock.ExpectExec("INSERT INTO todo_items \\(id, (.+)\\) VALUES \\(\\$1, (.+), \\$9\\), \\(\\$10, (.+), \\$18\\)").
+					WithArgs(7, nil, "first", "", false, nil, 0, nil, nil, 8, nil, "second", "", false, nil, todo.PriorityHigh, nil, nil).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(position\\), 0\\) FROM lists_items").WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(2048))
+				mock.ExpectExec("INSERT INTO lists_items \\(list_id, item_id, position\\) VALUES \\(\\$1, \\$2, \\$3\\), \\(\\$1, \\$4, \\$5\\)").
+					WithArgs(3, 7, 3072, 8, 4096).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO activity (.+) VALUES (.+), \\(\\$6, \\$7, \\$8, \\$9, \\$10\\)").
+					WithArgs(3, 7, 1, todo.ActionItemCreated, sqlmock.AnyArg(), 3, 8, 1, todo.ActionItemCreated, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectQuery("UPDATE todo_items ti SET deleted_at = now\\(\\)").WithArgs(1, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id", "parent_id", "deleted_at"}).AddRow(3, nil, deletedAt))
+				mock.ExpectExec("WITH RECURSIVE subtree AS (.+) UPDATE todo_items ti SET deleted_at = \\$2").
+					WithArgs(4, deletedAt).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO activity").WithArgs(3, 4, 1, todo.ActionItemDeleted, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			want: []todo.BatchResult{
+				{Op: todo.BatchCreate, ItemId: 7},
+				{Op: todo.BatchCreate, ItemId: 8},
+				{Op: todo.BatchDelete, ItemId: 4},
+			},
+		},
+		{
+			name: "Item Of Another List",
+			ops:  []todo.BatchOperation{{Op: todo.BatchUpdate, ItemId: 4, Update: &todo.UpdateItemInput{Done: boolPointer(true)}}},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+) FOR UPDATE OF tl").WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) FOR UPDATE OF ti").WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(4, 5))
+				mock.ExpectRollback()
+			},
+			wantErrIs: todo.ErrValidation,
+		},
+		{
+			name:    "Partial",
+			ops:     []todo.BatchOperation{{Op: todo.BatchCreate, Item: &todo.TodoItem{}}, {Op: todo.BatchDelete, ItemId: 4}},
+			partial: true,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+) FOR UPDATE OF tl").WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectExec("SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("UPDATE todo_items ti SET deleted_at = now\\(\\)").WithArgs(1, 4).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			want: []todo.BatchResult{
+				{Op: todo.BatchCreate, Err: todo.ErrValidation},
+				{Op: todo.BatchDelete, ItemId: 4, Err: todo.ErrNotFound},
+			},
+		},
+		{
+			name: "Read Only List",
+			ops:  ops,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+) FOR UPDATE OF tl").WithArgs(1, 3).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErrIs: todo.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Batch(context.Background(), 1, 3, testCase.ops, testCase.partial)
+			if testCase.wantErrIs != nil {
+				assert.ErrorIs(t, err, testCase.wantErrIs)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, got, len(testCase.want))
+				for n, result := range got {
+					assert.Equal(t, testCase.want[n].Op, result.Op)
+					assert.Equal(t, testCase.want[n].ItemId, result.ItemId)
+					assert.ErrorIs(t, result.Err, testCase.want[n].Err)
+				}
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
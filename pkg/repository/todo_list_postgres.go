@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/jmoiron/sqlx"
@@ -8,6 +9,8 @@ import (
 	"strings"
 )
 
+const listColumns = "tl.id, tl.title, tl.description, tl.created_at, tl.updated_at, tl.deleted_at"
+
 type TodoListPostgres struct {
 	db *sqlx.DB
 }
@@ -16,94 +19,351 @@ func NewTodoListPostgres(db *sqlx.DB) *TodoListPostgres {
 	return &TodoListPostgres{db: db}
 }
 
-func (r *TodoListPostgres) Create(userId int, list todo.TodoList) (int, error) {
-	tx, err := r.db.Begin()
+func (r *TodoListPostgres) Create(ctx context.Context, userId int, list todo.TodoList) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := createList(ctx, tx, userId, list)
 	if err != nil {
+		tx.Rollback()
 		return 0, err
 	}
 
+	return id, tx.Commit()
+}
+
+func createList(ctx context.Context, tx *sqlx.Tx, userId int, list todo.TodoList) (int, error) {
 	var id int
 	createListQuery := fmt.Sprintf("INSERT INTO %s (title, description) VALUES ($1, $2) RETURNING id", todoListsTable)
-	row := tx.QueryRow(createListQuery, list.Title, list.Description)
-	if err = row.Scan(&id); err != nil {
+	row := tx.QueryRowContext(ctx, createListQuery, list.Title, list.Description)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id, role) VALUES ($1, $2, $3)", usersListsTable)
+	if _, err := tx.ExecContext(ctx, createUsersListQuery, userId, id, todo.RoleOwner); err != nil {
+		return 0, err
+	}
+
+	changes := todo.Changes{}
+	addChange(changes, "title", nil, list.Title)
+	addChange(changes, "description", nil, list.Description)
+	err := logActivity(ctx, tx, todo.Activity{ListId: id, UserId: &userId, Action: todo.ActionListCreated, Changes: changes})
+	return id, err
+}
+
+// Import creates a list of the user with the items, which keep their order,
+// nesting and done state, in one transaction. It takes a statement per level
+// of subtasks and one per other table.
+func (r *TodoListPostgres) Import(ctx context.Context, userId int, list todo.ListExport) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	listId, err := createList(ctx, tx, userId, todo.TodoList{Title: list.Title, Description: list.Description})
+	if err != nil {
 		tx.Rollback()
 		return 0, err
 	}
 
-	createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id) VALUES ($1, $2)", usersListsTable)
-	_, err = tx.Exec(createUsersListQuery, userId, id)
+	rows := flattenItems(list.Items, -1, 1, nil)
+	if len(rows) == 0 {
+		return listId, tx.Commit()
+	}
+
+	ids, err := reserveItemIds(ctx, tx, len(rows))
 	if err != nil {
 		tx.Rollback()
 		return 0, err
 	}
+	for depth := 1; ; depth++ {
+		var level []int
+		for n, row := range rows {
+			if row.depth == depth {
+				level = append(level, n)
+			}
+		}
+		if len(level) == 0 {
+			break
+		}
+		if err = importItems(ctx, tx, rows, ids, level); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
 
-	return id, tx.Commit()
+	values := make([]string, len(rows))
+	args := []interface{}{listId}
+	activities := make([]todo.Activity, len(rows))
+	for n, row := range rows {
+		values[n] = fmt.Sprintf("($1, $%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, ids[n], int64(n+1)*positionGap)
+
+		changes := createdChanges(todo.TodoItem{
+			ParentId: row.parentId(ids), Title: row.item.Title, Description: row.item.Description,
+			DueAt: row.item.DueAt, Priority: row.item.Priority,
+		})
+		addChange(changes, "done", false, row.item.Done)
+		activities[n] = todo.Activity{
+			ListId: listId, ItemId: &ids[n], UserId: &userId, Action: todo.ActionItemCreated, Changes: changes,
+		}
+	}
+	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id, position) VALUES %s",
+		listsItemsTable, strings.Join(values, ", "))
+	if _, err = tx.ExecContext(ctx, createListItemsQuery, args...); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err = logActivities(ctx, tx, activities); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return listId, tx.Commit()
+}
+
+// importRow is an imported item with the index of its parent among the rows,
+// or -1.
+type importRow struct {
+	item   todo.ExportItem
+	parent int
+	depth  int
+}
+
+func (r importRow) parentId(ids []int) *int {
+	if r.parent < 0 {
+		return nil
+	}
+	return &ids[r.parent]
+}
+
+// flattenItems appends the items and their subtasks to rows in preorder.
+func flattenItems(items []todo.ExportItem, parent, depth int, rows []importRow) []importRow {
+	for _, item := range items {
+		rows = append(rows, importRow{item: item, parent: parent, depth: depth})
+		rows = flattenItems(item.Children, len(rows)-1, depth+1, rows)
+	}
+	return rows
 }
 
-func (r *TodoListPostgres) GetAll(userId int) ([]todo.TodoList, error) {
+// importItems inserts the rows of a level, whose parents are inserted
+// already, with their reserved ids.
+func importItems(ctx context.Context, tx *sqlx.Tx, rows []importRow, ids []int, level []int) error {
+	values := make([]string, len(level))
+	args := make([]interface{}, 0, len(level)*7)
+	for k, n := range level {
+		item := rows[n].item
+		values[k] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			len(args)+1, len(args)+2, len(args)+3, len(args)+4, len(args)+5, len(args)+6, len(args)+7)
+		args = append(args, ids[n], rows[n].parentId(ids), item.Title, item.Description, item.Done, item.DueAt, item.Priority)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (id, parent_id, title, description, done, due_at, priority) VALUES %s",
+		todoItemsTable, strings.Join(values, ", "))
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *TodoListPostgres) GetAll(ctx context.Context, userId int, query todo.ListQuery) ([]todo.TodoList, todo.PageInfo, error) {
+	var page todo.PageInfo
+	conditions := []string{"ul.user_id = $1", "tl.deleted_at IS NULL"}
+	args := []interface{}{userId}
+	argId := 2
+
+	if query.Title != "" {
+		conditions = append(conditions, fmt.Sprintf("tl.title ILIKE $%d", argId))
+		args = append(args, likePattern(query.Title))
+		argId++
+	}
+
+	countQuery := fmt.Sprintf("SELECT count(*) FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE %s",
+		todoListsTable, usersListsTable, strings.Join(conditions, " AND "))
+	if err := r.db.GetContext(ctx, &page.Total, countQuery, args...); err != nil {
+		return nil, page, fmt.Errorf("failed with GetAll count: %w", err)
+	}
+
+	keyset, keysetArgs, err := keysetCondition("tl", query, argId)
+	if err != nil {
+		return nil, page, err
+	}
+	if keyset != "" {
+		conditions = append(conditions, keyset)
+		args = append(args, keysetArgs...)
+		argId += len(keysetArgs)
+	}
+
 	var lists []todo.TodoList
-	query := fmt.Sprintf("SELECT tl.id, tl.title, tl.description FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1",
-		todoListsTable, usersListsTable)
-	err := r.db.Select(&lists, query, userId)
+	selectQuery := fmt.Sprintf(`SELECT %s FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id
+									WHERE %s %s LIMIT $%d`,
+		listColumns, todoListsTable, usersListsTable, strings.Join(conditions, " AND "), orderClause("tl", query), argId)
+	args = append(args, query.Limit+1)
+
+	err = r.db.SelectContext(ctx, &lists, selectQuery, args...)
 	if err != nil {
-		return lists, fmt.Errorf("failed with GetAll: %w", err)
+		return lists, page, fmt.Errorf("failed with GetAll: %w", err)
+	}
+
+	if len(lists) > query.Limit {
+		lists = lists[:query.Limit]
+		last := lists[len(lists)-1]
+		page.NextCursor = encodeCursor(query.Sort, last.Id, sortValue(query.Sort, last.Title, last.CreatedAt, 0))
 	}
-	return lists, nil
+	return lists, page, nil
 }
 
-func (r *TodoListPostgres) GetById(userId, listId int) (todo.TodoList, error) {
+func (r *TodoListPostgres) GetById(ctx context.Context, userId, listId int) (todo.TodoList, error) {
 	var list todo.TodoList
 
-	query := fmt.Sprintf("SELECT tl.id, tl.title, tl.description FROM %s tl "+
-		"INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2",
-		todoListsTable, usersListsTable)
-	err := r.db.Get(&list, query, userId, listId)
+	query := fmt.Sprintf("SELECT %s FROM %s tl "+
+		"INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2 AND tl.deleted_at IS NULL",
+		listColumns, todoListsTable, usersListsTable)
+	err := r.db.GetContext(ctx, &list, query, userId, listId)
 	if err != nil {
-		return list, fmt.Errorf("failed with GetById: %w", err)
+		return list, notFound(fmt.Errorf("failed with GetById: %w", err), "list")
 	}
 	return list, nil
 }
 
-func (r *TodoListPostgres) Delete(userId, listId int) error {
-	query := fmt.Sprintf("DELETE FROM %s tl USING %s ul WHERE tl.id = ul.list_id AND ul.user_id=$1 AND ul.list_id=$2",
-		todoListsTable, usersListsTable)
+// Delete moves the list and its items to the trash. The items get the same
+// deleted_at as the list, which is how Restore tells them from items that
+// were deleted on their own before.
+func (r *TodoListPostgres) Delete(ctx context.Context, userId, listId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	deleteListQuery := fmt.Sprintf(`UPDATE %s tl SET deleted_at = now() FROM %s ul
+									WHERE tl.id = ul.list_id AND ul.user_id=$1 AND ul.list_id=$2 AND %s AND tl.deleted_at IS NULL`,
+		todoListsTable, usersListsTable, ownerAccess)
+	res, err := tx.ExecContext(ctx, deleteListQuery, userId, listId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err = affected(res, "list"); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	deleteItemsQuery := fmt.Sprintf(`UPDATE %s ti SET deleted_at = now() FROM %s li
+									WHERE ti.id = li.item_id AND li.list_id = $1 AND ti.deleted_at IS NULL`,
+		todoItemsTable, listsItemsTable)
+	if _, err = tx.ExecContext(ctx, deleteItemsQuery, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = logActivity(ctx, tx, todo.Activity{ListId: listId, UserId: &userId, Action: todo.ActionListDeleted})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Restore brings a deleted list back out of the trash with the items that
+// were deleted together with it.
+func (r *TodoListPostgres) Restore(ctx context.Context, userId, listId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	restoreItemsQuery := fmt.Sprintf(`UPDATE %s ti SET deleted_at = NULL FROM %s li, %s tl, %s ul
+									WHERE ti.id = li.item_id AND tl.id = li.list_id AND ul.list_id = tl.id
+									AND ul.user_id = $1 AND tl.id = $2 AND %s AND ti.deleted_at = tl.deleted_at`,
+		todoItemsTable, listsItemsTable, todoListsTable, usersListsTable, ownerAccess)
+	if _, err = tx.ExecContext(ctx, restoreItemsQuery, userId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	_, err := r.db.Exec(query, userId, listId)
+	restoreListQuery := fmt.Sprintf(`UPDATE %s tl SET deleted_at = NULL FROM %s ul
+									WHERE tl.id = ul.list_id AND ul.user_id=$1 AND ul.list_id=$2 AND %s AND tl.deleted_at IS NOT NULL`,
+		todoListsTable, usersListsTable, ownerAccess)
+	res, err := tx.ExecContext(ctx, restoreListQuery, userId, listId)
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
-	return nil
+	if err = affected(res, "list"); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = logActivity(ctx, tx, todo.Activity{ListId: listId, UserId: &userId, Action: todo.ActionListRestored})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *TodoListPostgres) Update(userId, listId int, input todo.UpdateListInput) error {
+func (r *TodoListPostgres) Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var old todo.TodoList
+	selectQuery := fmt.Sprintf(`SELECT %s FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id
+									WHERE ul.user_id = $1 AND ul.list_id = $2 AND %s AND tl.deleted_at IS NULL FOR UPDATE OF tl`,
+		listColumns, todoListsTable, usersListsTable, writeAccess)
+	if err = tx.GetContext(ctx, &old, selectQuery, userId, listId); err != nil {
+		tx.Rollback()
+		return notFound(err, "list")
+	}
+
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
+	changes := todo.Changes{}
 
 	if input.Title != nil {
 		setValues = append(setValues, fmt.Sprintf("title=$%d", argId))
 		args = append(args, *input.Title)
 		argId++
+		addChange(changes, "title", old.Title, *input.Title)
 	}
 
 	if input.Description != nil {
 		setValues = append(setValues, fmt.Sprintf("description=$%d", argId))
 		args = append(args, *input.Description)
 		argId++
+		addChange(changes, "description", old.Description, *input.Description)
 	}
 
 	// title=$1
 	// description=$1
 	// title=$1, description=$2
+	setValues = append(setValues, "updated_at=now()")
 	setQuery := strings.Join(setValues, ", ")
 
-	query := fmt.Sprintf("UPDATE %s tl SET %s FROM %s ul WHERE tl.id = ul.list_id AND ul.list_id=$%d AND ul.user_id=$%d",
-		todoListsTable, setQuery, usersListsTable, argId, argId+1)
-	args = append(args, listId, userId)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d", todoListsTable, setQuery, argId)
+	args = append(args, listId)
 
 	log.Debug().Msgf("updateQuery: %s", query)
 	log.Debug().Msgf("args: %s", args)
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(changes) > 0 {
+		err = logActivity(ctx, tx, todo.Activity{ListId: listId, UserId: &userId, Action: todo.ActionListUpdated, Changes: changes})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
 	"testing"
+	"time"
 )
 
 func TestTodoListPostgres_Create(t *testing.T) {
@@ -38,7 +40,12 @@ func TestTodoListPostgres_Create(t *testing.T) {
 				mock.ExpectQuery("INSERT INTO todo_lists").
 					WithArgs("title", "description").WillReturnRows(rows)
 
-				mock.ExpectExec("INSERT INTO users_lists").WithArgs(1, 1).
+				mock.ExpectExec("INSERT INTO users_lists").WithArgs(1, 1, "owner").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(1, nil, 1, todo.ActionListCreated,
+						`{"description":{"from":null,"to":"description"},"title":{"from":null,"to":"title"}}`).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 				mock.ExpectCommit()
@@ -78,7 +85,7 @@ func TestTodoListPostgres_Create(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.Create(testCase.input.userId, testCase.input.item)
+			got, err := r.Create(context.Background(), testCase.input.userId, testCase.input.item)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -101,34 +108,80 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 
 	type args struct {
 		userId int
+		query  todo.ListQuery
 	}
 
+	createdAt := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
 	testTable := []struct {
-		name    string
-		mock    func()
-		input   args
-		want    []todo.TodoList
-		wantErr bool
+		name     string
+		mock     func()
+		input    args
+		want     []todo.TodoList
+		wantPage todo.PageInfo
+		wantErr  bool
 	}{
 		{
 			name: "OK",
 			mock: func() {
+				mock.ExpectQuery("SELECT count(.+) FROM todo_lists tl INNER JOIN users_lists ul on (.+) WHERE (.+)").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
 				rows := sqlmock.NewRows([]string{"id", "title", "description"}).
 					AddRow(1, "title1", "description1").
 					AddRow(2, "title2", "description2").
 					AddRow(3, "title3", "description3")
 
 				mock.ExpectQuery("SELECT (.+) FROM todo_lists tl INNER JOIN users_lists ul on (.+) WHERE (.+)").
-					WithArgs(1).WillReturnRows(rows)
+					WithArgs(1, 51).WillReturnRows(rows)
+			},
+			input: args{
+				userId: 1,
+				query:  todo.ListQuery{Limit: 50, Sort: "id", Order: "asc"},
+			},
+			want: []todo.TodoList{
+				{Id: 1, Title: "title1", Description: "description1"},
+				{Id: 2, Title: "title2", Description: "description2"},
+				{Id: 3, Title: "title3", Description: "description3"},
+			},
+			wantPage: todo.PageInfo{Total: 3},
+		},
+		{
+			name: "First Page By Created",
+			mock: func() {
+				mock.ExpectQuery("SELECT count(.+) FROM todo_lists tl INNER JOIN users_lists ul on (.+) WHERE (.+)").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "created_at"}).
+					AddRow(1, "title1", "description1", createdAt).
+					AddRow(2, "title2", "description2", createdAt.Add(time.Hour))
+
+				mock.ExpectQuery("SELECT (.+) ORDER BY tl.created_at ASC, tl.id ASC LIMIT (.+)").
+					WithArgs(1, 2).WillReturnRows(rows)
 			},
 			input: args{
 				userId: 1,
+				query:  todo.ListQuery{Limit: 1, Sort: "created_at", Order: "asc"},
 			},
 			want: []todo.TodoList{
-				{1, "title1", "description1"},
-				{2, "title2", "description2"},
-				{3, "title3", "description3"},
+				{Id: 1, Title: "title1", Description: "description1", CreatedAt: createdAt},
+			},
+			wantPage: todo.PageInfo{
+				NextCursor: encodeCursor("created_at", 1, createdAt.Format(time.RFC3339Nano)),
+				Total:      3,
+			},
+		},
+		{
+			name: "Invalid Cursor",
+			mock: func() {
+				mock.ExpectQuery("SELECT count(.+) FROM todo_lists tl INNER JOIN users_lists ul on (.+) WHERE (.+)").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 			},
+			input: args{
+				userId: 1,
+				query:  todo.ListQuery{Limit: 50, Cursor: "not a cursor", Sort: "id", Order: "asc"},
+			},
+			wantErr: true,
 		},
 	}
 
@@ -136,12 +189,13 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.GetAll(testCase.input.userId)
+			got, page, err := r.GetAll(context.Background(), testCase.input.userId, testCase.input.query)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, testCase.want, got)
+				assert.Equal(t, testCase.wantPage, page)
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -162,18 +216,21 @@ func TestTodoListPostgres_GetById(t *testing.T) {
 		userId int
 	}
 
+	createdAt := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
 	testTable := []struct {
-		name    string
-		mock    func()
-		input   args
-		want    todo.TodoList
-		wantErr bool
+		name      string
+		mock      func()
+		input     args
+		want      todo.TodoList
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "title", "description"}).
-					AddRow(1, "title1", "description1")
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "created_at", "updated_at"}).
+					AddRow(1, "title1", "description1", createdAt, createdAt.Add(time.Hour))
 
 				mock.ExpectQuery("SELECT (.+) FROM todo_lists tl INNER JOIN users_lists ul on (.+) WHERE (.+)").
 					WithArgs(1, 1).WillReturnRows(rows)
@@ -182,7 +239,13 @@ func TestTodoListPostgres_GetById(t *testing.T) {
 				listId: 1,
 				userId: 1,
 			},
-			want: todo.TodoList{1, "title1", "description1"},
+			want: todo.TodoList{
+				Id:          1,
+				Title:       "title1",
+				Description: "description1",
+				CreatedAt:   createdAt,
+				UpdatedAt:   createdAt.Add(time.Hour),
+			},
 		},
 		{
 			name: "NOT FOUND",
@@ -196,16 +259,20 @@ func TestTodoListPostgres_GetById(t *testing.T) {
 				listId: 404,
 				userId: 1,
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
 		},
 	}
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.GetById(testCase.input.userId, testCase.input.listId)
+			got, err := r.GetById(context.Background(), testCase.input.userId, testCase.input.listId)
 			if testCase.wantErr {
 				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, testCase.want, got)
@@ -230,16 +297,27 @@ func TestTodoListPostgres_Delete(t *testing.T) {
 	}
 
 	testTable := []struct {
-		name    string
-		mock    func()
-		input   args
-		wantErr bool
+		name      string
+		mock      func()
+		input     args
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				mock.ExpectExec("DELETE FROM todo_lists tl USING users_lists ul WHERE (.+)").
+				mock.ExpectBegin()
+
+				mock.ExpectExec("UPDATE todo_lists tl SET deleted_at = now\\(\\) FROM users_lists ul WHERE (.+)").
 					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec("UPDATE todo_items ti SET deleted_at = now\\(\\) FROM lists_items li WHERE (.+)").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(1, nil, 1, todo.ActionListDeleted, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
 			},
 			input: args{
 				listId: 1,
@@ -249,14 +327,101 @@ func TestTodoListPostgres_Delete(t *testing.T) {
 		{
 			name: "Not Found",
 			mock: func() {
-				mock.ExpectExec("DELETE FROM todo_lists tl USING users_lists ul WHERE (.+)").
-					WithArgs(1, 404).WillReturnError(sql.ErrNoRows)
+				mock.ExpectBegin()
+
+				mock.ExpectExec("UPDATE todo_lists tl SET deleted_at = now\\(\\) FROM users_lists ul WHERE (.+)").
+					WithArgs(1, 404).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectRollback()
 			},
 			input: args{
 				listId: 404,
 				userId: 1,
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Delete(context.Background(), testCase.input.userId, testCase.input.listId)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoListPostgres_Restore(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewTodoListPostgres(db)
+
+	type args struct {
+		listId int
+		userId int
+	}
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		input     args
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectExec("UPDATE todo_items ti SET deleted_at = NULL FROM (.+) WHERE (.+) AND ti.deleted_at = tl.deleted_at").
+					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 3))
+
+				mock.ExpectExec("UPDATE todo_lists tl SET deleted_at = NULL FROM users_lists ul WHERE (.+)").
+					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(1, nil, 1, todo.ActionListRestored, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+			},
+			input: args{
+				listId: 1,
+				userId: 1,
+			},
+		},
+		{
+			name: "Not In Trash",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectExec("UPDATE todo_items ti SET deleted_at = NULL FROM (.+) WHERE (.+)").
+					WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec("UPDATE todo_lists tl SET deleted_at = NULL FROM users_lists ul WHERE (.+)").
+					WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectRollback()
+			},
+			input: args{
+				listId: 2,
+				userId: 1,
+			},
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
 		},
 	}
 
@@ -264,9 +429,12 @@ func TestTodoListPostgres_Delete(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			err := r.Delete(testCase.input.userId, testCase.input.listId)
+			err := r.Restore(context.Background(), testCase.input.userId, testCase.input.listId)
 			if testCase.wantErr {
 				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -289,17 +457,32 @@ func TestTodoListPostgres_Update(t *testing.T) {
 		userId int
 		input  todo.UpdateListInput
 	}
+
+	oldList := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "title", "description", "created_at", "updated_at", "deleted_at"}).
+			AddRow(1, "old title", "old description", time.Time{}, time.Time{}, nil)
+	}
+
 	testTable := []struct {
-		name    string
-		mock    func()
-		input   args
-		wantErr bool
+		name      string
+		mock      func()
+		input     args
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "OK",
 			mock: func() {
-				mock.ExpectExec("UPDATE todo_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
-					WithArgs("new title", "new description", 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_lists tl (.+) FOR UPDATE OF tl").
+					WithArgs(1, 1).WillReturnRows(oldList())
+				mock.ExpectExec("UPDATE todo_lists SET (.+) WHERE id=(.+)").
+					WithArgs("new title", "new description", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(1, nil, 1, todo.ActionListUpdated, `{"description":{"from":"old description","to":"new description"},`+
+						`"title":{"from":"old title","to":"new title"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			input: args{
 				listId: 1,
@@ -313,8 +496,15 @@ func TestTodoListPostgres_Update(t *testing.T) {
 		{
 			name: "OK no description",
 			mock: func() {
-				mock.ExpectExec("UPDATE todo_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
-					WithArgs("new title", 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_lists tl (.+) FOR UPDATE OF tl").
+					WithArgs(1, 1).WillReturnRows(oldList())
+				mock.ExpectExec("UPDATE todo_lists SET (.+) WHERE id=(.+)").
+					WithArgs("new title", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(1, nil, 1, todo.ActionListUpdated, `{"title":{"from":"old title","to":"new title"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			input: args{
 				listId: 1,
@@ -325,41 +515,155 @@ func TestTodoListPostgres_Update(t *testing.T) {
 			},
 		},
 		{
-			name: "OK no title",
+			name: "OK unchanged title",
 			mock: func() {
-				mock.ExpectExec("UPDATE todo_lists tl SET (.+) FROM users_lists ul WHERE (.+)").
-					WithArgs("new description", 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_lists tl (.+) FOR UPDATE OF tl").
+					WithArgs(1, 1).WillReturnRows(oldList())
+				mock.ExpectExec("UPDATE todo_lists SET (.+) WHERE id=(.+)").
+					WithArgs("old title", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 			input: args{
 				listId: 1,
 				userId: 1,
 				input: todo.UpdateListInput{
-					Description: stringPointer("new description"),
+					Title: stringPointer("old title"),
 				},
 			},
 		},
 		{
 			name: "OK_NoInputFields",
 			mock: func() {
-				mock.ExpectExec("UPDATE todo_lists tl SET FROM users_lists ul WHERE (.+)").
-					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_lists tl (.+) FOR UPDATE OF tl").
+					WithArgs(1, 1).WillReturnRows(oldList())
+				mock.ExpectExec("UPDATE todo_lists SET updated_at=now\\(\\) WHERE id=(.+)").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 			input: args{
 				listId: 1,
 				userId: 1,
 			},
 		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM todo_lists tl (.+) FOR UPDATE OF tl").
+					WithArgs(1, 404).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			input: args{
+				listId: 404,
+				userId: 1,
+				input: todo.UpdateListInput{
+					Title: stringPointer("new title"),
+				},
+			},
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Update(context.Background(), testCase.input.userId, testCase.input.listId, testCase.input.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoListPostgres_Import(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewTodoListPostgres(db)
+
+	list := todo.ListExport{
+		Title: "Release",
+		Items: []todo.ExportItem{
+			{Title: "Freeze", Priority: todo.PriorityHigh, Children: []todo.ExportItem{{Title: "Tag", Done: true}}},
+			{Title: "Announce"},
+		},
+	}
+	expectList := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO todo_lists").WithArgs("Release", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec("INSERT INTO users_lists").WithArgs(1, 3, todo.RoleOwner).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO activity").WithArgs(3, nil, 1, todo.ActionListCreated, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				expectList()
+				mock.ExpectQuery("SELECT nextval(.+) FROM generate_series").WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(10).AddRow(11).AddRow(12))
+				mock.ExpectExec("INSERT INTO todo_items \\(id, (.+)\\) VALUES (.+), \\(\\$8, (.+)\\)").
+					WithArgs(10, nil, "Freeze", "", false, nil, todo.PriorityHigh, 12, nil, "Announce", "", false, nil, 0).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO todo_items \\(id, (.+)\\) VALUES \\(\\$1, (.+), \\$7\\)").
+					WithArgs(11, 10, "Tag", "", true, nil, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(3, 10, 1024, 11, 2048, 12, 3072).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("INSERT INTO activity").
+					WithArgs(3, 10, 1, todo.ActionItemCreated, sqlmock.AnyArg(),
+						3, 11, 1, todo.ActionItemCreated, `{"description":{"from":null,"to":""},"done":{"from":false,"to":true},`+
+							`"parent_id":{"from":null,"to":10},"priority":{"from":null,"to":0},"title":{"from":null,"to":"Tag"}}`,
+						3, 12, 1, todo.ActionItemCreated, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+			want: 3,
+		},
+		{
+			name: "Insert Error",
+			mock: func() {
+				expectList()
+				mock.ExpectQuery("SELECT nextval(.+) FROM generate_series").WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(10).AddRow(11).AddRow(12))
+				mock.ExpectExec("INSERT INTO todo_items").WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			err := r.Update(testCase.input.userId, testCase.input.listId, testCase.input.input)
+			got, err := r.Import(context.Background(), 1, list)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
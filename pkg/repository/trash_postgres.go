@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type TrashPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTrashPostgres(db *sqlx.DB) *TrashPostgres {
+	return &TrashPostgres{db: db}
+}
+
+// GetAll returns the deleted lists the user can access and the items deleted
+// on their own from lists and parents that are not deleted, most recently
+// deleted first.
+func (r *TrashPostgres) GetAll(ctx context.Context, userId int) (todo.Trash, error) {
+	trash := todo.Trash{
+		Lists: []todo.TodoList{},
+		Items: []todo.TodoItem{},
+	}
+
+	listsQuery := fmt.Sprintf(`SELECT %s FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id
+									WHERE ul.user_id = $1 AND tl.deleted_at IS NOT NULL ORDER BY tl.deleted_at DESC, tl.id`,
+		listColumns, todoListsTable, usersListsTable)
+	if err := r.db.SelectContext(ctx, &trash.Lists, listsQuery, userId); err != nil {
+		return trash, fmt.Errorf("failed to get deleted lists: %w", err)
+	}
+
+	itemsQuery := fmt.Sprintf(`SELECT %s FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+									WHERE ul.user_id = $1 AND ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL
+									AND NOT EXISTS (SELECT 1 FROM %s p WHERE p.id = ti.parent_id AND p.deleted_at IS NOT NULL)
+									ORDER BY ti.deleted_at DESC, ti.id`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, todoListsTable, todoItemsTable)
+	if err := r.db.SelectContext(ctx, &trash.Items, itemsQuery, userId); err != nil {
+		return trash, fmt.Errorf("failed to get deleted items: %w", err)
+	}
+
+	return trash, nil
+}
+
+// Purge permanently removes the lists and items deleted longer than retention
+// ago on the database clock and returns how many rows it removed.
+func (r *TrashPostgres) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	for _, table := range []string{todoItemsTable, todoListsTable} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < now() - make_interval(secs => $1)", table)
+		res, err := tx.ExecContext(ctx, query, retention.Seconds())
+		if err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to purge %s: %w", table, err)
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		purged += rows
+	}
+
+	return purged, tx.Commit()
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestTrashPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewTrashPostgres(db)
+
+	deletedAt := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT (.+) FROM todo_lists tl INNER JOIN users_lists ul on (.+) WHERE (.+) tl.deleted_at IS NOT NULL").
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}).
+		AddRow(1, "list", deletedAt))
+
+	mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) WHERE (.+) ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL").
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}))
+
+	got, err := r.GetAll(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, todo.Trash{
+		Lists: []todo.TodoList{{Id: 1, Title: "list", DeletedAt: &deletedAt}},
+		Items: []todo.TodoItem{},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrashPostgres_Purge(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewTrashPostgres(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM todo_items WHERE deleted_at < now\\(\\) - make_interval\\(secs => \\$1\\)").
+		WithArgs(float64(86400)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM todo_lists WHERE deleted_at < now\\(\\) - make_interval\\(secs => \\$1\\)").
+		WithArgs(float64(86400)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	got, err := r.Purge(context.Background(), 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+const webhookDeliveryColumns = "d.id, d.webhook_id, d.event, d.payload, d.attempts, d.status_code, d.last_error, " +
+	"d.next_attempt_at, d.delivered_at, d.failed_at, d.created_at"
+
+type WebhookDeliveryPostgres struct {
+	db *sqlx.DB
+}
+
+func NewWebhookDeliveryPostgres(db *sqlx.DB) *WebhookDeliveryPostgres {
+	return &WebhookDeliveryPostgres{db: db}
+}
+
+// GetAll returns the delivery log of a webhook, newest first. Ownership of
+// the webhook is checked by the caller.
+func (r *WebhookDeliveryPostgres) GetAll(ctx context.Context, webhookId int, query todo.PageQuery) ([]todo.WebhookDelivery, todo.PageInfo, error) {
+	var page todo.PageInfo
+	conditions := "d.webhook_id = $1"
+	args := []interface{}{webhookId}
+
+	countQuery := fmt.Sprintf("SELECT count(*) FROM %s d WHERE %s", webhookDeliveriesTable, conditions)
+	if err := r.db.GetContext(ctx, &page.Total, countQuery, args...); err != nil {
+		return nil, page, fmt.Errorf("failed with GetAll deliveries count: %w", err)
+	}
+
+	if query.Cursor != "" {
+		c, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, page, err
+		}
+		if c.Sort != "id" {
+			return nil, page, fmt.Errorf("%w: cursor does not match sort", todo.ErrValidation)
+		}
+		conditions += " AND d.id < $2"
+		args = append(args, c.Id)
+	}
+
+	var deliveries []todo.WebhookDelivery
+	selectQuery := fmt.Sprintf("SELECT %s FROM %s d WHERE %s ORDER BY d.id DESC LIMIT $%d",
+		webhookDeliveryColumns, webhookDeliveriesTable, conditions, len(args)+1)
+	args = append(args, query.Limit+1)
+
+	if err := r.db.SelectContext(ctx, &deliveries, selectQuery, args...); err != nil {
+		return nil, page, fmt.Errorf("failed with GetAll deliveries: %w", err)
+	}
+
+	if len(deliveries) > query.Limit {
+		deliveries = deliveries[:query.Limit]
+		page.NextCursor = encodeCursor("id", deliveries[len(deliveries)-1].Id, "")
+	}
+	return deliveries, page, nil
+}
+
+// Enqueue queues the payload for every enabled webhook subscribed to its
+// event whose user has access to the list.
+func (r *WebhookDeliveryPostgres) Enqueue(ctx context.Context, payload todo.WebhookPayload) (int64, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (webhook_id, event, payload)
+									SELECT w.id, $1::text, $2::jsonb FROM %s w INNER JOIN %s ul on ul.user_id = w.user_id
+									WHERE ul.list_id = $3 AND w.disabled_at IS NULL AND $1::text = ANY(w.events)`,
+		webhookDeliveriesTable, webhooksTable, usersListsTable)
+
+	res, err := r.db.ExecContext(ctx, query, payload.Event, string(body), payload.ListId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Claim leases up to limit pending deliveries of enabled webhooks to the
+// caller, like ReminderPostgres.Claim.
+func (r *WebhookDeliveryPostgres) Claim(ctx context.Context, limit int, lease time.Duration) ([]todo.WebhookDelivery, error) {
+	var deliveries []todo.WebhookDelivery
+	query := fmt.Sprintf(`UPDATE %s d SET attempts = d.attempts + 1, next_attempt_at = now() + make_interval(secs => $1)
+									FROM (SELECT pd.id FROM %s pd INNER JOIN %s pw on pw.id = pd.webhook_id
+										WHERE pd.delivered_at IS NULL AND pd.failed_at IS NULL AND pd.next_attempt_at <= now()
+										AND pw.disabled_at IS NULL
+										ORDER BY pd.next_attempt_at LIMIT $2 FOR UPDATE OF pd SKIP LOCKED) due, %s w
+									WHERE d.id = due.id AND w.id = d.webhook_id
+									RETURNING %s, w.url, w.secret`,
+		webhookDeliveriesTable, webhookDeliveriesTable, webhooksTable, webhooksTable, webhookDeliveryColumns)
+
+	if err := r.db.SelectContext(ctx, &deliveries, query, lease.Seconds(), limit); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// MarkDelivered also resets the failures of the webhook.
+func (r *WebhookDeliveryPostgres) MarkDelivered(ctx context.Context, id, statusCode int) error {
+	query := fmt.Sprintf(`WITH d AS (UPDATE %s SET delivered_at = now(), status_code = $1, last_error = NULL
+									WHERE id = $2 RETURNING webhook_id)
+									UPDATE %s w SET failures = 0 FROM d WHERE w.id = d.webhook_id`,
+		webhookDeliveriesTable, webhooksTable)
+	_, err := r.db.ExecContext(ctx, query, statusCode, id)
+	return err
+}
+
+// MarkFailed schedules the next attempt after retryIn on the database clock,
+// or gives the delivery up when retryIn is nil. The webhook is disabled once
+// disableAfter attempts in a row have failed. A statusCode of 0 means there
+// was no response.
+func (r *WebhookDeliveryPostgres) MarkFailed(ctx context.Context, id, statusCode int, reason string, retryIn *time.Duration, disableAfter int) error {
+	var retrySecs *float64
+	if retryIn != nil {
+		secs := retryIn.Seconds()
+		retrySecs = &secs
+	}
+
+	query := fmt.Sprintf(`WITH d AS (UPDATE %s SET status_code = NULLIF($1, 0), last_error = $2,
+										next_attempt_at = COALESCE(now() + make_interval(secs => $3::float8), next_attempt_at),
+										failed_at = CASE WHEN $3::float8 IS NULL THEN now() END
+									WHERE id = $4 RETURNING webhook_id)
+									UPDATE %s w SET failures = w.failures + 1,
+										disabled_at = CASE WHEN w.failures + 1 >= $5 THEN COALESCE(w.disabled_at, now()) ELSE w.disabled_at END
+									FROM d WHERE w.id = d.webhook_id`,
+		webhookDeliveriesTable, webhooksTable)
+	_, err := r.db.ExecContext(ctx, query, statusCode, reason, retrySecs, id, disableAfter)
+	return err
+}
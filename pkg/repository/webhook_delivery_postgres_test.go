@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+	"time"
+)
+
+func TestWebhookDeliveryPostgres_Enqueue(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewWebhookDeliveryPostgres(db)
+
+	createdAt := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	payload := todo.WebhookPayload{Event: todo.ActionListCreated, ListId: 3, ActorId: 1, CreatedAt: createdAt}
+
+	mock.ExpectExec("INSERT INTO webhook_deliveries (.+) SELECT (.+) FROM webhooks w INNER JOIN users_lists ul (.+) = ANY\\(w.events\\)").
+		WithArgs(todo.ActionListCreated, `{"event":"list_created","list_id":3,"actor_id":1,"created_at":"2023-07-01T12:00:00Z"}`, 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	queued, err := r.Enqueue(context.Background(), payload)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), queued)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookDeliveryPostgres_Claim(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewWebhookDeliveryPostgres(db)
+
+	rows := sqlmock.NewRows([]string{"id", "webhook_id", "event", "payload", "attempts", "status_code", "last_error",
+		"next_attempt_at", "delivered_at", "failed_at", "created_at", "url", "secret"}).
+		AddRow(1, 2, todo.ActionItemCreated, []byte(`{"event":"item_created"}`), 1, nil, nil,
+			time.Time{}, nil, nil, time.Time{}, "https://example.com/hook", "secret")
+
+	mock.ExpectQuery("UPDATE webhook_deliveries d SET attempts = d.attempts \\+ 1(.+) FOR UPDATE OF pd SKIP LOCKED(.+) RETURNING (.+)").
+		WithArgs(float64(60), 10).WillReturnRows(rows)
+
+	got, err := r.Claim(context.Background(), 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, json.RawMessage(`{"event":"item_created"}`), got[0].Payload)
+	assert.Equal(t, "https://example.com/hook", got[0].URL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookDeliveryPostgres_MarkFailed(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewWebhookDeliveryPostgres(db)
+
+	retryIn := 2 * time.Minute
+
+	mock.ExpectExec("WITH d AS \\(UPDATE webhook_deliveries (.+)COALESCE\\(now\\(\\) \\+ make_interval\\(secs => \\$3::float8\\)(.+)\\) UPDATE webhooks w SET failures = w.failures \\+ 1(.+)").
+		WithArgs(503, "webhook responded with status 503", float64(120), 1, 20).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, r.MarkFailed(context.Background(), 1, 503, "webhook responded with status 503", &retryIn, 20))
+
+	mock.ExpectExec("WITH d AS \\(UPDATE webhook_deliveries (.+)").
+		WithArgs(0, "timeout", nil, 2, 20).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, r.MarkFailed(context.Background(), 2, 0, "timeout", nil, 20))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"strings"
+)
+
+const webhookColumns = "id, url, events, failures, disabled_at, created_at, updated_at"
+
+type WebhookPostgres struct {
+	db *sqlx.DB
+}
+
+func NewWebhookPostgres(db *sqlx.DB) *WebhookPostgres {
+	return &WebhookPostgres{db: db}
+}
+
+func (r *WebhookPostgres) Create(ctx context.Context, userId int, input todo.WebhookInput) (int, error) {
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (user_id, url, secret, events) VALUES ($1, $2, $3, $4) RETURNING id", webhooksTable)
+	row := r.db.QueryRowContext(ctx, query, userId, input.URL, input.Secret, pq.Array(input.Events))
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *WebhookPostgres) GetAll(ctx context.Context, userId int) ([]todo.Webhook, error) {
+	var webhooks []todo.Webhook
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 ORDER BY id", webhookColumns, webhooksTable)
+	if err := r.db.SelectContext(ctx, &webhooks, query, userId); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookPostgres) GetById(ctx context.Context, userId, webhookId int) (todo.Webhook, error) {
+	var webhook todo.Webhook
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 AND id = $2", webhookColumns, webhooksTable)
+	if err := r.db.GetContext(ctx, &webhook, query, userId, webhookId); err != nil {
+		return webhook, notFound(err, "webhook")
+	}
+	return webhook, nil
+}
+
+func (r *WebhookPostgres) Update(ctx context.Context, userId, webhookId int, input todo.UpdateWebhookInput) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.URL != nil {
+		setValues = append(setValues, fmt.Sprintf("url=$%d", argId))
+		args = append(args, *input.URL)
+		argId++
+	}
+
+	if input.Secret != nil {
+		setValues = append(setValues, fmt.Sprintf("secret=$%d", argId))
+		args = append(args, *input.Secret)
+		argId++
+	}
+
+	if input.Events != nil {
+		setValues = append(setValues, fmt.Sprintf("events=$%d", argId))
+		args = append(args, pq.Array(*input.Events))
+		argId++
+	}
+
+	if input.Active != nil {
+		if *input.Active {
+			setValues = append(setValues, "disabled_at=NULL", "failures=0")
+		} else {
+			setValues = append(setValues, "disabled_at=COALESCE(disabled_at, now())")
+		}
+	}
+
+	setValues = append(setValues, "updated_at=now()")
+	setQuery := strings.Join(setValues, ", ")
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE user_id=$%d AND id=$%d", webhooksTable, setQuery, argId, argId+1)
+	args = append(args, userId, webhookId)
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return affected(res, "webhook")
+}
+
+func (r *WebhookPostgres) Delete(ctx context.Context, userId, webhookId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 AND id = $2", webhooksTable)
+	res, err := r.db.ExecContext(ctx, query, userId, webhookId)
+	if err != nil {
+		return err
+	}
+	return affected(res, "webhook")
+}
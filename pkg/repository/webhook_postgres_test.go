@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"testing"
+)
+
+func TestWebhookPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewWebhookPostgres(db)
+
+	input := todo.WebhookInput{
+		URL:    "https://example.com/hook",
+		Secret: "0123456789abcdef",
+		Events: []string{todo.ActionItemCreated, todo.ActionItemDone},
+	}
+
+	mock.ExpectQuery("INSERT INTO webhooks").
+		WithArgs(1, input.URL, input.Secret, "{\"item_created\",\"item_completed\"}").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	id, err := r.Create(context.Background(), 1, input)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed with stub db conn")
+	}
+	defer db.Close()
+
+	r := NewWebhookPostgres(db)
+
+	type args struct {
+		userId    int
+		webhookId int
+		input     todo.UpdateWebhookInput
+	}
+
+	testTable := []struct {
+		name      string
+		mock      func()
+		input     args
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE webhooks SET url=\\$1, events=\\$2, updated_at=now\\(\\) WHERE user_id=\\$3 AND id=\\$4").
+					WithArgs("https://example.com/new", pq.StringArray{todo.ActionListCreated}, 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{
+				userId:    1,
+				webhookId: 2,
+				input: todo.UpdateWebhookInput{
+					URL:    stringPointer("https://example.com/new"),
+					Events: &[]string{todo.ActionListCreated},
+				},
+			},
+		},
+		{
+			name: "Enable",
+			mock: func() {
+				mock.ExpectExec("UPDATE webhooks SET disabled_at=NULL, failures=0, updated_at=now\\(\\) WHERE (.+)").
+					WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{
+				userId:    1,
+				webhookId: 2,
+				input:     todo.UpdateWebhookInput{Active: boolPointer(true)},
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE webhooks SET (.+) WHERE (.+)").
+					WithArgs(1, 404).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			input: args{
+				userId:    1,
+				webhookId: 404,
+				input:     todo.UpdateWebhookInput{Active: boolPointer(false)},
+			},
+			wantErr:   true,
+			wantErrIs: todo.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Update(context.Background(), testCase.input.userId, testCase.input.webhookId, testCase.input.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				if testCase.wantErrIs != nil {
+					assert.ErrorIs(t, err, testCase.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+)
+
+type ActivityService struct {
+	repo     repository.Activity
+	listRepo repository.TodoList
+}
+
+func NewActivityService(repo repository.Activity, listRepo repository.TodoList) *ActivityService {
+	return &ActivityService{repo: repo, listRepo: listRepo}
+}
+
+func (s *ActivityService) GetAll(ctx context.Context, userId, listId int, query todo.PageQuery) ([]todo.Activity, todo.PageInfo, error) {
+	if err := query.Validate(); err != nil {
+		return nil, todo.PageInfo{}, err
+	}
+
+	_, err := s.listRepo.GetById(ctx, userId, listId)
+	if err != nil {
+		return nil, todo.PageInfo{}, err
+	}
+
+	return s.repo.GetAll(ctx, listId, query)
+}
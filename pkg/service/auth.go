@@ -1,62 +1,159 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/LittleMikle/ToDo_List/pkg/repository"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/crypto/sha3"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	salt      = "sdg2klASs1Dlkjd0fmZd34dfgASc"
-	signInKey = "SDfjk%0ncvk347dfs7sd72as!sd&"
-	tokenTTL  = 12 * time.Hour
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+	passwordCost    = bcrypt.DefaultCost
 )
 
+var errInvalidCredentials = fmt.Errorf("%w: invalid username or password", todo.ErrUnauthorized)
+
+var errInvalidRefreshToken = fmt.Errorf("%w: invalid refresh token", todo.ErrUnauthorized)
+
+// dummyPasswordHash is compared with the password of unknown users, so that
+// signing in as one takes as long as with a wrong password.
+const dummyPasswordHash = "$2a$10$04wTmFnt2Q/gcGttuh/xDuKCNivlt4g4RSHsavsVnG7fWHAsqgSEq"
+
 type tokenClaims struct {
 	jwt.RegisteredClaims
-	ExpiresAt int64
-	IssuedAt  int64
 	UserId    int `json:"user_id"`
+	SessionId int `json:"sid"`
+}
+
+type AuthConfig struct {
+	Keys *KeySet
+	// LegacySalt is the constant salt of pre-bcrypt SHA3 password hashes.
+	LegacySalt string
 }
 
 type AuthService struct {
-	repo repository.Authorization
+	repo        repository.Authorization
+	sessionRepo repository.Session
+	cfg         AuthConfig
 }
 
-func NewAuthService(repo repository.Authorization) *AuthService {
-	return &AuthService{repo: repo}
+func NewAuthService(repo repository.Authorization, sessionRepo repository.Session, cfg AuthConfig) *AuthService {
+	return &AuthService{repo: repo, sessionRepo: sessionRepo, cfg: cfg}
 }
 
-func (s *AuthService) CreateUser(user todo.User) (int, error) {
-	user.Password = generatePasswordHash(user.Password)
-	return s.repo.CreateUser(user)
+func (s *AuthService) CreateUser(ctx context.Context, user todo.User) (int, error) {
+	hash, err := generatePasswordHash(user.Password)
+	if err != nil {
+		return 0, err
+	}
+
+	user.Password = hash
+	return s.repo.CreateUser(ctx, user)
 }
 
-func (s *AuthService) GenerateToken(username, password string) (string, error) {
-	user, err := s.repo.GetUser(username, generatePasswordHash(password))
+func (s *AuthService) GenerateToken(ctx context.Context, username, password string) (todo.Tokens, error) {
+	user, err := s.repo.GetUser(ctx, username)
+	if errors.Is(err, todo.ErrNotFound) {
+		bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+		return todo.Tokens{}, errInvalidCredentials
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token:%w", err)
+		return todo.Tokens{}, fmt.Errorf("failed to generate token:%w", err)
+	}
+
+	if err = s.checkPassword(ctx, user, password); err != nil {
+		return todo.Tokens{}, fmt.Errorf("failed to generate token:%w", err)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
-		ExpiresAt: time.Now().Add(tokenTTL).Unix(),
-		IssuedAt:  time.Now().Unix(),
-		UserId:    user.Id,
+	secret, err := newRefreshSecret()
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	sessionId, err := s.sessionRepo.Create(ctx, todo.Session{
+		UserId:           user.Id,
+		RefreshTokenHash: hashToken(secret),
+		ExpiresAt:        time.Now().Add(refreshTokenTTL),
 	})
-	return token.SignedString([]byte(signInKey))
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	return s.issueTokens(user.Id, sessionId, secret)
 }
 
-func (s *AuthService) ParseToken(accessToken string) (int, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
+// RefreshToken rotates the refresh token of a session and issues a new pair.
+// Presenting the refresh token that was rotated away last means it leaked, so
+// the whole session is revoked. Any other secret is refused without touching
+// the session, as session ids are easy to guess.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (todo.Tokens, error) {
+	sessionId, secret, err := splitRefreshToken(refreshToken)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	session, err := s.activeSession(ctx, sessionId)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	if !refreshSecretMatches(secret, session.RefreshTokenHash) {
+		if session.PreviousTokenHash != nil && refreshSecretMatches(secret, *session.PreviousTokenHash) {
+			return todo.Tokens{}, s.revokeReusedSession(ctx, sessionId)
 		}
-		return []byte(signInKey), nil
-	})
+		return todo.Tokens{}, errInvalidRefreshToken
+	}
+
+	newSecret, err := newRefreshSecret()
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	rotated, err := s.sessionRepo.Rotate(ctx, sessionId, session.RefreshTokenHash, hashToken(newSecret),
+		time.Now().Add(refreshTokenTTL))
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+	if !rotated {
+		return todo.Tokens{}, s.revokeReusedSession(ctx, sessionId)
+	}
+
+	return s.issueTokens(session.UserId, sessionId, newSecret)
+}
+
+func (s *AuthService) RevokeToken(ctx context.Context, refreshToken string) error {
+	sessionId, secret, err := splitRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.sessionRepo.GetById(ctx, sessionId)
+	if err != nil {
+		return sessionError(err)
+	}
+
+	if !refreshSecretMatches(secret, session.RefreshTokenHash) {
+		return errInvalidRefreshToken
+	}
+
+	return s.sessionRepo.Revoke(ctx, sessionId)
+}
+
+func (s *AuthService) ParseToken(ctx context.Context, accessToken string) (int, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, s.cfg.Keys.Keyfunc)
 	if err != nil {
 		return 0, fmt.Errorf("failed with Parse with claims: %w", err)
 	}
@@ -66,10 +163,142 @@ func (s *AuthService) ParseToken(accessToken string) (int, error) {
 		return 0, errors.New("token claims are not of type *tokenClaims")
 	}
 
+	if _, err = s.activeSession(ctx, claims.SessionId); err != nil {
+		return 0, err
+	}
+
 	return claims.UserId, nil
 }
 
-func generatePasswordHash(password string) string {
+func (s *AuthService) JWKS() todo.JWKS {
+	return s.cfg.Keys.JWKS()
+}
+
+func (s *AuthService) issueTokens(userId, sessionId int, secret string) (todo.Tokens, error) {
+	now := time.Now()
+	accessToken, err := s.cfg.Keys.Sign(&tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		UserId:    userId,
+		SessionId: sessionId,
+	})
+	if err != nil {
+		return todo.Tokens{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return todo.Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: fmt.Sprintf("%d.%s", sessionId, secret),
+	}, nil
+}
+
+func (s *AuthService) activeSession(ctx context.Context, sessionId int) (todo.Session, error) {
+	session, err := s.sessionRepo.GetById(ctx, sessionId)
+	if err != nil {
+		return session, sessionError(err)
+	}
+
+	if session.RevokedAt != nil {
+		return session, fmt.Errorf("%w: session is revoked", todo.ErrUnauthorized)
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return session, fmt.Errorf("%w: session is expired", todo.ErrUnauthorized)
+	}
+	return session, nil
+}
+
+// sessionError tells a token of a missing session from a failed lookup.
+func sessionError(err error) error {
+	if errors.Is(err, todo.ErrNotFound) {
+		return fmt.Errorf("%w: %s", todo.ErrUnauthorized, err)
+	}
+	return err
+}
+
+func (s *AuthService) revokeReusedSession(ctx context.Context, sessionId int) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionId); err != nil {
+		return fmt.Errorf("failed to revoke reused session: %w", err)
+	}
+	return fmt.Errorf("%w: refresh token reuse detected, session revoked", todo.ErrUnauthorized)
+}
+
+func newRefreshSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func refreshSecretMatches(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(hash)) == 1
+}
+
+// splitRefreshToken parses a "<session id>.<secret>" refresh token.
+func splitRefreshToken(refreshToken string) (int, string, error) {
+	idPart, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || secret == "" {
+		return 0, "", errInvalidRefreshToken
+	}
+
+	sessionId, err := strconv.Atoi(idPart)
+	if err != nil {
+		return 0, "", errInvalidRefreshToken
+	}
+	return sessionId, secret, nil
+}
+
+// checkPassword verifies the password against the stored hash. Legacy SHA3
+// hashes and bcrypt hashes below the current cost are upgraded on success.
+func (s *AuthService) checkPassword(ctx context.Context, user todo.User, password string) error {
+	if isLegacyPasswordHash(user.Password) {
+		if subtle.ConstantTimeCompare([]byte(legacyPasswordHash(password, s.cfg.LegacySalt)), []byte(user.Password)) != 1 {
+			return errInvalidCredentials
+		}
+		s.rehashPassword(ctx, user.Id, password)
+		return nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errInvalidCredentials
+	}
+	if cost, err := bcrypt.Cost([]byte(user.Password)); err == nil && cost < passwordCost {
+		s.rehashPassword(ctx, user.Id, password)
+	}
+	return nil
+}
+
+// rehashPassword stores a fresh hash of a verified password. Failures are only
+// logged: the user is already authenticated and the upgrade is retried next time.
+func (s *AuthService) rehashPassword(ctx context.Context, userId int, password string) {
+	hash, err := generatePasswordHash(password)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to rehash password of user %d", userId)
+		return
+	}
+
+	if err = s.repo.UpdatePasswordHash(ctx, userId, hash); err != nil {
+		log.Error().Err(err).Msgf("failed to store rehashed password of user %d", userId)
+	}
+}
+
+func generatePasswordHash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// isLegacyPasswordHash reports whether the hash predates bcrypt. Bcrypt
+// hashes are prefixed with the "$2a$"/"$2b$" version marker, SHA3 ones are hex.
+func isLegacyPasswordHash(hash string) bool {
+	return !strings.HasPrefix(hash, "$2")
+}
+
+func legacyPasswordHash(password, salt string) string {
 	hash := sha3.New256()
 	hash.Write([]byte(password))
 	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeSessionRepo struct {
+	session todo.Session
+	revoked bool
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session todo.Session) (int, error) {
+	return r.session.Id, nil
+}
+
+func (r *fakeSessionRepo) GetById(ctx context.Context, sessionId int) (todo.Session, error) {
+	if sessionId != r.session.Id {
+		return todo.Session{}, todo.ErrNotFound
+	}
+	return r.session, nil
+}
+
+func (r *fakeSessionRepo) Rotate(ctx context.Context, sessionId int, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeSessionRepo) Revoke(ctx context.Context, sessionId int) error {
+	r.revoked = true
+	return nil
+}
+
+func TestAuthService_RefreshToken_WrongSecret(t *testing.T) {
+	previousHash := hashToken("previous")
+
+	testTable := []struct {
+		name        string
+		token       string
+		wantRevoked bool
+	}{
+		{
+			name:  "Unknown Secret",
+			token: "1.guessed",
+		},
+		{
+			name:        "Reused Secret",
+			token:       "1.previous",
+			wantRevoked: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			sessions := &fakeSessionRepo{session: todo.Session{
+				Id:                1,
+				UserId:            2,
+				RefreshTokenHash:  hashToken("current"),
+				PreviousTokenHash: &previousHash,
+				ExpiresAt:         time.Now().Add(time.Hour),
+			}}
+			s := NewAuthService(nil, sessions, AuthConfig{})
+
+			_, err := s.RefreshToken(context.Background(), testCase.token)
+
+			assert.ErrorIs(t, err, todo.ErrUnauthorized)
+			assert.Equal(t, testCase.wantRevoked, sessions.revoked)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+)
+
+// defaultCalendarName names the feed of all lists of a user.
+const defaultCalendarName = "Todo"
+
+type CalendarService struct {
+	repo     repository.Calendar
+	itemRepo repository.TodoItem
+	listRepo repository.TodoList
+}
+
+func NewCalendarService(repo repository.Calendar, itemRepo repository.TodoItem, listRepo repository.TodoList) *CalendarService {
+	return &CalendarService{repo: repo, itemRepo: itemRepo, listRepo: listRepo}
+}
+
+// CreateToken returns a new feed token of the user, which revokes the
+// previous one. Only its hash is stored, so it can't be shown again.
+func (s *CalendarService) CreateToken(ctx context.Context, userId int) (string, error) {
+	token, err := newCalendarToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.repo.SetToken(ctx, userId, hashToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *CalendarService) DeleteToken(ctx context.Context, userId int) error {
+	return s.repo.DeleteToken(ctx, userId)
+}
+
+// Feed returns the dated items of the user the token belongs to, in one of
+// their lists if the query has one.
+func (s *CalendarService) Feed(ctx context.Context, token string, query todo.CalendarQuery) (todo.CalendarFeed, error) {
+	if err := query.Validate(); err != nil {
+		return todo.CalendarFeed{}, err
+	}
+
+	userId, err := s.repo.GetUserId(ctx, hashToken(token))
+	if err != nil {
+		return todo.CalendarFeed{}, err
+	}
+
+	feed := todo.CalendarFeed{Name: defaultCalendarName}
+	if query.ListId != nil {
+		list, err := s.listRepo.GetById(ctx, userId, *query.ListId)
+		if err != nil {
+			return todo.CalendarFeed{}, err
+		}
+		feed.Name = list.Title
+	}
+
+	feed.Items, err = s.itemRepo.GetDated(ctx, userId, query.ListId)
+	if err != nil {
+		return todo.CalendarFeed{}, err
+	}
+	return feed, nil
+}
+
+func newCalendarToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
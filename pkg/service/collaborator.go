@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+)
+
+type CollaboratorService struct {
+	repo repository.Collaborator
+}
+
+func NewCollaboratorService(repo repository.Collaborator) *CollaboratorService {
+	return &CollaboratorService{repo: repo}
+}
+
+func (s *CollaboratorService) Share(ctx context.Context, userId, listId int, input todo.ShareListInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Share(ctx, userId, listId, input.Username, input.Role)
+}
+
+func (s *CollaboratorService) GetAll(ctx context.Context, userId, listId int) ([]todo.Collaborator, error) {
+	return s.repo.GetAll(ctx, userId, listId)
+}
+
+func (s *CollaboratorService) Remove(ctx context.Context, userId, listId, collaboratorId int) error {
+	return s.repo.Remove(ctx, userId, listId, collaboratorId)
+}
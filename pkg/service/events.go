@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"github.com/LittleMikle/ToDo_List/pkg/events"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+)
+
+type EventsService struct {
+	subscriber events.Subscriber
+	listRepo   repository.TodoList
+}
+
+func NewEventsService(subscriber events.Subscriber, listRepo repository.TodoList) *EventsService {
+	return &EventsService{subscriber: subscriber, listRepo: listRepo}
+}
+
+func (s *EventsService) Subscribe(ctx context.Context, userId, listId int) (<-chan events.Event, func(), error) {
+	_, err := s.listRepo.GetById(ctx, userId, listId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch, unsubscribe := s.subscriber.Subscribe(listId)
+	return ch, unsubscribe, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"os"
+	"sort"
+)
+
+// minSecretLength is the shortest HS256 secret accepted, in bytes, as shorter
+// HMAC keys are weaker than the hash they key.
+const minSecretLength = 32
+
+// KeyConfig describes one JWT key. HS256 keys read their secret from the
+// SecretEnv environment variable, RS256 and EdDSA keys are PEM files. A key
+// with only a public key file can verify tokens but never sign them, which is
+// how a retired key stays valid until the tokens it issued have expired.
+type KeyConfig struct {
+	Kid            string `mapstructure:"kid"`
+	Alg            string `mapstructure:"alg"`
+	SecretEnv      string `mapstructure:"secret_env"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+}
+
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	sign   interface{}
+	verify interface{}
+}
+
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+func NewKeySet(activeKid string, configs []KeyConfig) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*signingKey, len(configs))}
+
+	for _, cfg := range configs {
+		if cfg.Kid == "" {
+			return nil, errors.New("jwt key without kid")
+		}
+		if _, ok := set.keys[cfg.Kid]; ok {
+			return nil, fmt.Errorf("duplicate jwt key %q", cfg.Kid)
+		}
+
+		key, err := loadKey(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt key %q: %w", cfg.Kid, err)
+		}
+		set.keys[cfg.Kid] = key
+	}
+
+	active, ok := set.keys[activeKid]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q is not configured", activeKid)
+	}
+	if active.sign == nil {
+		return nil, fmt.Errorf("active jwt key %q has no private key", activeKid)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.method, claims)
+	token.Header["kid"] = k.active.kid
+	return token.SignedString(k.active.sign)
+}
+
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+	return key.verify, nil
+}
+
+// JWKS returns the public keys of the set. HMAC keys are never published.
+func (k *KeySet) JWKS() todo.JWKS {
+	jwks := todo.JWKS{Keys: make([]todo.JWK, 0, len(k.keys))}
+
+	for _, key := range k.keys {
+		jwk := todo.JWK{Use: "sig", Kid: key.kid, Alg: key.method.Alg()}
+
+		switch pub := key.verify.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+	return jwks
+}
+
+func loadKey(cfg KeyConfig) (*signingKey, error) {
+	key := &signingKey{kid: cfg.Kid}
+
+	switch cfg.Alg {
+	case jwt.SigningMethodHS256.Alg():
+		secret := os.Getenv(cfg.SecretEnv)
+		if secret == "" {
+			return nil, fmt.Errorf("environment variable %q is empty", cfg.SecretEnv)
+		}
+		if len(secret) < minSecretLength {
+			return nil, fmt.Errorf("environment variable %q is shorter than %d bytes", cfg.SecretEnv, minSecretLength)
+		}
+		key.method = jwt.SigningMethodHS256
+		key.sign = []byte(secret)
+		key.verify = []byte(secret)
+	case jwt.SigningMethodRS256.Alg():
+		key.method = jwt.SigningMethodRS256
+		if cfg.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.sign = private
+			key.verify = &private.PublicKey
+		} else {
+			pem, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			public, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.verify = public
+		}
+	case jwt.SigningMethodEdDSA.Alg():
+		key.method = jwt.SigningMethodEdDSA
+		if cfg.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.sign = private
+			key.verify = private.(ed25519.PrivateKey).Public()
+		} else {
+			pem, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			public, err := jwt.ParseEdPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.verify = public
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", cfg.Alg)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+)
+
+type LabelService struct {
+	repo     repository.Label
+	itemRepo repository.TodoItem
+}
+
+func NewLabelService(repo repository.Label, itemRepo repository.TodoItem) *LabelService {
+	return &LabelService{repo: repo, itemRepo: itemRepo}
+}
+
+func (s *LabelService) Create(ctx context.Context, userId int, input todo.LabelInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+
+	return s.repo.Create(ctx, userId, input)
+}
+
+func (s *LabelService) GetAll(ctx context.Context, userId int) ([]todo.Label, error) {
+	return s.repo.GetAll(ctx, userId)
+}
+
+func (s *LabelService) GetById(ctx context.Context, userId, labelId int) (todo.Label, error) {
+	return s.repo.GetById(ctx, userId, labelId)
+}
+
+func (s *LabelService) Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, userId, labelId, input)
+}
+
+func (s *LabelService) Delete(ctx context.Context, userId, labelId int) error {
+	return s.repo.Delete(ctx, userId, labelId)
+}
+
+func (s *LabelService) GetByItem(ctx context.Context, userId, itemId int) ([]todo.Label, error) {
+	if _, err := s.itemRepo.GetById(ctx, userId, itemId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByItem(ctx, userId, itemId)
+}
+
+// Attach needs read access to the item only, as labels are private to their
+// user.
+func (s *LabelService) Attach(ctx context.Context, userId, itemId, labelId int) error {
+	if err := s.checkAccess(ctx, userId, itemId, labelId); err != nil {
+		return err
+	}
+
+	return s.repo.Attach(ctx, userId, itemId, labelId)
+}
+
+func (s *LabelService) Detach(ctx context.Context, userId, itemId, labelId int) error {
+	if err := s.checkAccess(ctx, userId, itemId, labelId); err != nil {
+		return err
+	}
+
+	return s.repo.Detach(ctx, userId, itemId, labelId)
+}
+
+func (s *LabelService) checkAccess(ctx context.Context, userId, itemId, labelId int) error {
+	if _, err := s.itemRepo.GetById(ctx, userId, itemId); err != nil {
+		return err
+	}
+
+	_, err := s.repo.GetById(ctx, userId, labelId)
+	return err
+}
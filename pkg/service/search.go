@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+)
+
+type SearchService struct {
+	repo repository.Search
+}
+
+func NewSearchService(repo repository.Search) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) Search(ctx context.Context, userId int, query todo.SearchQuery) ([]todo.SearchResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Search(ctx, userId, query)
+}
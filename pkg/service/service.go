@@ -1,44 +1,128 @@
 package service
 
 import (
+	"context"
 	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/events"
 	"github.com/LittleMikle/ToDo_List/pkg/repository"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
 type Authorization interface {
-	CreateUser(user todo.User) (int, error)
-	GenerateToken(username, password string) (string, error)
-	ParseToken(token string) (int, error)
+	CreateUser(ctx context.Context, user todo.User) (int, error)
+	GenerateToken(ctx context.Context, username, password string) (todo.Tokens, error)
+	RefreshToken(ctx context.Context, refreshToken string) (todo.Tokens, error)
+	RevokeToken(ctx context.Context, refreshToken string) error
+	ParseToken(ctx context.Context, token string) (int, error)
+	JWKS() todo.JWKS
 }
 
 type TodoList interface {
-	Create(userId int, list todo.TodoList) (int, error)
-	GetAll(userId int) ([]todo.TodoList, error)
-	GetById(userId, listId int) (todo.TodoList, error)
-	Delete(userId, listId int) error
-	Update(userId, listId int, input todo.UpdateListInput) error
+	Create(ctx context.Context, userId int, list todo.TodoList) (int, error)
+	GetAll(ctx context.Context, userId int, query todo.ListQuery) ([]todo.TodoList, todo.PageInfo, error)
+	GetById(ctx context.Context, userId, listId int) (todo.TodoList, error)
+	Delete(ctx context.Context, userId, listId int) error
+	Restore(ctx context.Context, userId, listId int) error
+	Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error
+	Export(ctx context.Context, userId, listId int) (todo.ListExport, error)
+	Import(ctx context.Context, userId int, list todo.ListExport) (int, error)
+}
+
+type Collaborator interface {
+	Share(ctx context.Context, userId, listId int, input todo.ShareListInput) error
+	GetAll(ctx context.Context, userId, listId int) ([]todo.Collaborator, error)
+	Remove(ctx context.Context, userId, listId, collaboratorId int) error
 }
 
 type TodoItem interface {
-	Create(userId, listId int, item todo.TodoItem) (int, error)
-	GetAll(userId, listId int) ([]todo.TodoItem, error)
-	GetById(userId, itemId int) (todo.TodoItem, error)
-	Delete(userId, itemId int) error
-	Update(userId, itemId int, input todo.UpdateItemInput) error
+	Create(ctx context.Context, userId, listId int, item todo.TodoItem) (int, error)
+	GetAll(ctx context.Context, userId, listId int, query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error)
+	GetAllForUser(ctx context.Context, userId int, query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error)
+	GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error)
+	GetTree(ctx context.Context, userId, listId int) ([]todo.ItemNode, error)
+	GetSubtasks(ctx context.Context, userId, itemId int) (todo.ItemNode, error)
+	GetOverdue(ctx context.Context, userId int) ([]todo.TodoItem, error)
+	GetDueThisWeek(ctx context.Context, userId int) ([]todo.TodoItem, error)
+	Delete(ctx context.Context, userId, itemId int) error
+	Restore(ctx context.Context, userId, itemId int) error
+	Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) error
+	SetRecurrence(ctx context.Context, userId, itemId int, rule todo.Recurrence) error
+	StopRecurrence(ctx context.Context, userId, itemId int) error
+	Reorder(ctx context.Context, userId, listId int, input todo.ReorderItemsInput) error
+	Move(ctx context.Context, userId, itemId, listId int) error
+	Copy(ctx context.Context, userId, itemId, listId int) (int, error)
+	Batch(ctx context.Context, userId, listId int, input todo.BatchInput) ([]todo.BatchResult, error)
+}
+
+type Label interface {
+	Create(ctx context.Context, userId int, input todo.LabelInput) (int, error)
+	GetAll(ctx context.Context, userId int) ([]todo.Label, error)
+	GetById(ctx context.Context, userId, labelId int) (todo.Label, error)
+	Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error
+	Delete(ctx context.Context, userId, labelId int) error
+	GetByItem(ctx context.Context, userId, itemId int) ([]todo.Label, error)
+	Attach(ctx context.Context, userId, itemId, labelId int) error
+	Detach(ctx context.Context, userId, itemId, labelId int) error
+}
+
+type Search interface {
+	Search(ctx context.Context, userId int, query todo.SearchQuery) ([]todo.SearchResult, error)
+}
+
+type Trash interface {
+	GetAll(ctx context.Context, userId int) (todo.Trash, error)
+}
+
+type Activity interface {
+	GetAll(ctx context.Context, userId, listId int, query todo.PageQuery) ([]todo.Activity, todo.PageInfo, error)
+}
+
+type Webhook interface {
+	Create(ctx context.Context, userId int, input todo.WebhookInput) (int, string, error)
+	GetAll(ctx context.Context, userId int) ([]todo.Webhook, error)
+	GetById(ctx context.Context, userId, webhookId int) (todo.Webhook, error)
+	Update(ctx context.Context, userId, webhookId int, input todo.UpdateWebhookInput) error
+	Delete(ctx context.Context, userId, webhookId int) error
+	GetDeliveries(ctx context.Context, userId, webhookId int, query todo.PageQuery) ([]todo.WebhookDelivery, todo.PageInfo, error)
+}
+
+type Calendar interface {
+	CreateToken(ctx context.Context, userId int) (string, error)
+	DeleteToken(ctx context.Context, userId int) error
+	Feed(ctx context.Context, token string, query todo.CalendarQuery) (todo.CalendarFeed, error)
+}
+
+type Events interface {
+	Subscribe(ctx context.Context, userId, listId int) (<-chan events.Event, func(), error)
 }
 
 type Service struct {
 	Authorization
 	TodoList
+	Collaborator
 	TodoItem
+	Label
+	Search
+	Trash
+	Activity
+	Events
+	Webhook
+	Calendar
 }
 
-func NewService(repos *repository.Repository) *Service {
+func NewService(repos *repository.Repository, authCfg AuthConfig, broker events.Broker) *Service {
 	return &Service{
-		Authorization: NewAuthService(repos.Authorization),
-		TodoList:      NewTodoListService(repos.TodoList),
-		TodoItem:      NewTodoItemService(repos.TodoItem, repos.TodoList),
+		Authorization: NewAuthService(repos.Authorization, repos.Session, authCfg),
+		TodoList:      NewTodoListService(repos.TodoList, repos.TodoItem, repos.WebhookDelivery),
+		Collaborator:  NewCollaboratorService(repos.Collaborator),
+		TodoItem:      NewTodoItemService(repos.TodoItem, repos.TodoList, broker, repos.WebhookDelivery),
+		Label:         NewLabelService(repos.Label, repos.TodoItem),
+		Search:        NewSearchService(repos.Search),
+		Trash:         NewTrashService(repos.Trash),
+		Activity:      NewActivityService(repos.Activity, repos.TodoList),
+		Events:        NewEventsService(broker, repos.TodoList),
+		Webhook:       NewWebhookService(repos.Webhook, repos.WebhookDelivery),
+		Calendar:      NewCalendarService(repos.Calendar, repos.TodoItem, repos.TodoList),
 	}
 }
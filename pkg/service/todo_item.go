@@ -1,40 +1,442 @@
 package service
 
 import (
+	"context"
 	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/events"
 	"github.com/LittleMikle/ToDo_List/pkg/repository"
+	"github.com/rs/zerolog/log"
+	"time"
 )
 
 type TodoItemService struct {
-	repo     repository.TodoItem
-	listRepo repository.TodoList
+	repo       repository.TodoItem
+	listRepo   repository.TodoList
+	publisher  events.Publisher
+	deliveries repository.WebhookDelivery
 }
 
-func NewTodoItemService(repo repository.TodoItem, listRepo repository.TodoList) *TodoItemService {
-	return &TodoItemService{repo: repo, listRepo: listRepo}
+func NewTodoItemService(repo repository.TodoItem, listRepo repository.TodoList, publisher events.Publisher,
+	deliveries repository.WebhookDelivery) *TodoItemService {
+	return &TodoItemService{repo: repo, listRepo: listRepo, publisher: publisher, deliveries: deliveries}
 }
 
-func (s *TodoItemService) Create(userId, listId int, item todo.TodoItem) (int, error) {
-	_, err := s.listRepo.GetById(userId, listId)
+func (s *TodoItemService) Create(ctx context.Context, userId, listId int, item todo.TodoItem) (int, error) {
+	if err := item.Validate(); err != nil {
+		return 0, err
+	}
+
+	_, err := s.listRepo.GetById(ctx, userId, listId)
+	if err != nil {
+		return 0, err
+	}
+
+	parents := s.ancestors(ctx, userId, item)
+	id, err := s.repo.Create(ctx, userId, listId, item)
+	if err != nil {
+		return 0, err
+	}
+
+	s.notifyCurrent(ctx, userId, id, todo.ActionItemCreated)
+	s.notifyCompletion(ctx, userId, parents)
+	return id, nil
+}
+
+func (s *TodoItemService) GetAll(ctx context.Context, userId, listId int, query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error) {
+	query = query.WithDefaultSort(todo.ItemSorts)
+	if err := query.Validate(todo.ItemSorts); err != nil {
+		return nil, todo.PageInfo{}, err
+	}
+
+	return s.repo.GetAll(ctx, userId, listId, query)
+}
+
+func (s *TodoItemService) GetAllForUser(ctx context.Context, userId int, query todo.ListQuery) ([]todo.TodoItem, todo.PageInfo, error) {
+	query = query.WithDefaultSort(todo.AllItemSorts)
+	if err := query.Validate(todo.AllItemSorts); err != nil {
+		return nil, todo.PageInfo{}, err
+	}
+
+	return s.repo.GetAllForUser(ctx, userId, query)
+}
+
+func (s *TodoItemService) GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error) {
+	return s.repo.GetById(ctx, userId, itemId)
+}
+
+// GetTree returns the top-level items of the list with their subtasks.
+func (s *TodoItemService) GetTree(ctx context.Context, userId, listId int) ([]todo.ItemNode, error) {
+	if _, err := s.listRepo.GetById(ctx, userId, listId); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetTree(ctx, userId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemTree(items, nil), nil
+}
+
+// GetSubtasks returns the item with its subtasks.
+func (s *TodoItemService) GetSubtasks(ctx context.Context, userId, itemId int) (todo.ItemNode, error) {
+	item, err := s.repo.GetById(ctx, userId, itemId)
+	if err != nil {
+		return todo.ItemNode{}, err
+	}
+
+	items, err := s.repo.GetTree(ctx, userId, item.ListId)
+	if err != nil {
+		return todo.ItemNode{}, err
+	}
+
+	return todo.ItemNode{TodoItem: item, Children: itemTree(items, &item.Id)}, nil
+}
+
+func (s *TodoItemService) GetOverdue(ctx context.Context, userId int) ([]todo.TodoItem, error) {
+	return s.repo.GetDue(ctx, userId, nil, time.Now())
+}
+
+// GetDueThisWeek returns the undone items due from now until the end of the
+// current ISO week, which ends on Sunday at midnight UTC.
+func (s *TodoItemService) GetDueThisWeek(ctx context.Context, userId int) ([]todo.TodoItem, error) {
+	now := time.Now().UTC()
+	daysLeft := (7 - int(now.Weekday())) % 7
+	weekEnd := time.Date(now.Year(), now.Month(), now.Day()+daysLeft+1, 0, 0, 0, 0, time.UTC)
+
+	return s.repo.GetDue(ctx, userId, &now, weekEnd)
+}
+
+func (s *TodoItemService) Delete(ctx context.Context, userId, itemId int) error {
+	item, err := s.repo.GetById(ctx, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	parents := s.ancestors(ctx, userId, item)
+	if err = s.repo.Delete(ctx, userId, itemId); err != nil {
+		return err
+	}
+
+	s.notify(ctx, userId, todo.ActionItemDeleted, item)
+	s.notifyCompletion(ctx, userId, parents)
+	return nil
+}
+
+// Restore does not tell about the parents a restored subtask completes or
+// reopens, which show up in the activity of the list only.
+func (s *TodoItemService) Restore(ctx context.Context, userId, itemId int) error {
+	if err := s.repo.Restore(ctx, userId, itemId); err != nil {
+		return err
+	}
+
+	s.notifyCurrent(ctx, userId, itemId, todo.ActionItemRestored)
+	return nil
+}
+
+// Update creates the next occurrence of a repeating item it marks done.
+func (s *TodoItemService) Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	old, err := s.repo.GetById(ctx, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	parents := s.ancestors(ctx, userId, old)
+	nextId, err := s.repo.Update(ctx, userId, itemId, input)
+	if err != nil {
+		return err
+	}
+
+	item, err := s.repo.GetById(ctx, userId, itemId)
+	if err != nil {
+		log.Error().Err(err).Int("item_id", itemId).Msg("failed with loading item for notifications")
+		return nil
+	}
+
+	action := todo.ActionItemUpdated
+	if item.Done && !old.Done {
+		action = todo.ActionItemDone
+	}
+	s.notify(ctx, userId, action, item)
+	if nextId != 0 {
+		s.notifyCurrent(ctx, userId, nextId, todo.ActionItemCreated)
+	}
+	s.notifyCompletion(ctx, userId, parents)
+	return nil
+}
+
+func (s *TodoItemService) SetRecurrence(ctx context.Context, userId, itemId int, rule todo.Recurrence) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.SetRecurrence(ctx, userId, itemId, rule); err != nil {
+		return err
+	}
+
+	s.notifyCurrent(ctx, userId, itemId, todo.ActionItemUpdated)
+	return nil
+}
+
+func (s *TodoItemService) StopRecurrence(ctx context.Context, userId, itemId int) error {
+	if err := s.repo.StopRecurrence(ctx, userId, itemId); err != nil {
+		return err
+	}
+
+	s.notifyCurrent(ctx, userId, itemId, todo.ActionItemUpdated)
+	return nil
+}
+
+func (s *TodoItemService) Reorder(ctx context.Context, userId, listId int, input todo.ReorderItemsInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Reorder(ctx, userId, listId, input); err != nil {
+		return err
+	}
+
+	if err := s.publisher.Publish(ctx, events.Event{Type: events.ItemsReordered, ListId: listId}); err != nil {
+		log.Error().Err(err).Int("list_id", listId).Msg("failed with publishing event")
+	}
+	triggerWebhooks(ctx, s.deliveries, todo.WebhookPayload{
+		Event: todo.ActionItemsReordered, ListId: listId, ActorId: userId, Data: input,
+	})
+	return nil
+}
+
+func (s *TodoItemService) Move(ctx context.Context, userId, itemId, listId int) error {
+	old, err := s.repo.GetById(ctx, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	parents := s.ancestors(ctx, userId, old)
+	if err = s.repo.Move(ctx, userId, itemId, listId); err != nil {
+		return err
+	}
+
+	items, err := s.repo.GetTree(ctx, userId, listId)
+	if err != nil {
+		log.Error().Err(err).Int("item_id", itemId).Msg("failed with loading items for notifications")
+		return nil
+	}
+
+	// the subtasks moved along with the item
+	for _, item := range subtree(items, itemId) {
+		id := item.Id
+
+		// the item is gone for the subscribers of the old list, its webhooks
+		// still hear about the move
+		event := events.Event{Type: events.ItemDeleted, ListId: old.ListId, ItemId: id}
+		if err = s.publisher.Publish(ctx, event); err != nil {
+			log.Error().Err(err).Int("item_id", id).Msg("failed with publishing event")
+		}
+		triggerWebhooks(ctx, s.deliveries, todo.WebhookPayload{
+			Event: todo.ActionItemMoved, ListId: old.ListId, ItemId: &id, ActorId: userId, Data: item,
+		})
+
+		s.notify(ctx, userId, todo.ActionItemMoved, item)
+	}
+	s.notifyCompletion(ctx, userId, parents)
+	return nil
+}
+
+func (s *TodoItemService) Copy(ctx context.Context, userId, itemId, listId int) (int, error) {
+	id, err := s.repo.Copy(ctx, userId, itemId, listId)
 	if err != nil {
 		return 0, err
 	}
 
-	return s.repo.Create(listId, item)
+	s.notifyCurrent(ctx, userId, id, todo.ActionItemCreated)
+	return id, nil
 }
 
-func (s *TodoItemService) GetAll(userId, listId int) ([]todo.TodoItem, error) {
-	return s.repo.GetAll(userId, listId)
+// Batch tells about the items of a batch once it is committed. The items of
+// the list before and after it show which parents it completed or reopened.
+func (s *TodoItemService) Batch(ctx context.Context, userId, listId int, input todo.BatchInput) ([]todo.BatchResult, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	before, err := s.repo.GetTree(ctx, userId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.Batch(ctx, userId, listId, input.Operations, input.Partial)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := s.repo.GetTree(ctx, userId, listId)
+	if err != nil {
+		log.Error().Err(err).Int("list_id", listId).Msg("failed with loading items for notifications")
+		return results, nil
+	}
+
+	old := make(map[int]todo.TodoItem, len(before))
+	for _, item := range before {
+		old[item.Id] = item
+	}
+	current := make(map[int]todo.TodoItem, len(after))
+	for _, item := range after {
+		current[item.Id] = item
+	}
+
+	notified := make(map[int]bool)
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		notified[result.ItemId] = true
+		item, exists := current[result.ItemId]
+		prev, existed := old[result.ItemId]
+		switch {
+		case result.Op == todo.BatchCreate && exists:
+			s.notify(ctx, userId, todo.ActionItemCreated, item)
+		case result.Op == todo.BatchUpdate && exists:
+			action := todo.ActionItemUpdated
+			if item.Done && !prev.Done {
+				action = todo.ActionItemDone
+			}
+			s.notify(ctx, userId, action, item)
+		case result.Op == todo.BatchDelete && existed:
+			s.notify(ctx, userId, todo.ActionItemDeleted, prev)
+		}
+		if next, ok := current[result.NextItemId]; ok {
+			notified[next.Id] = true
+			s.notify(ctx, userId, todo.ActionItemCreated, next)
+		}
+	}
+
+	for _, item := range after {
+		if prev, ok := old[item.Id]; ok && !notified[item.Id] && prev.Done != item.Done {
+			action := todo.ActionItemUpdated
+			if item.Done {
+				action = todo.ActionItemDone
+			}
+			s.notify(ctx, userId, action, item)
+		}
+	}
+	return results, nil
+}
+
+// ancestors returns the parent of the item, its parent and so on.
+func (s *TodoItemService) ancestors(ctx context.Context, userId int, item todo.TodoItem) []todo.TodoItem {
+	var parents []todo.TodoItem
+	for parentId := item.ParentId; parentId != nil; {
+		parent, err := s.repo.GetById(ctx, userId, *parentId)
+		if err != nil {
+			break
+		}
+		parents = append(parents, parent)
+		parentId = parent.ParentId
+	}
+	return parents
+}
+
+// notifyCompletion notifies about the parents that were completed or reopened
+// along with their subtasks, given their state from before the change.
+func (s *TodoItemService) notifyCompletion(ctx context.Context, userId int, parents []todo.TodoItem) {
+	for _, old := range parents {
+		parent, err := s.repo.GetById(ctx, userId, old.Id)
+		if err != nil {
+			log.Error().Err(err).Int("item_id", old.Id).Msg("failed with loading item for notifications")
+			return
+		}
+		if parent.Done == old.Done {
+			return
+		}
+
+		action := todo.ActionItemUpdated
+		if parent.Done {
+			action = todo.ActionItemDone
+		}
+		s.notify(ctx, userId, action, parent)
+	}
 }
 
-func (s *TodoItemService) GetById(userId, itemId int) (todo.TodoItem, error) {
-	return s.repo.GetById(userId, itemId)
+// itemTree nests the items under the item parentId, or returns the top-level
+// ones when it is nil, keeping their order.
+func itemTree(items []todo.TodoItem, parentId *int) []todo.ItemNode {
+	children := make(map[int][]todo.TodoItem)
+	var roots []todo.TodoItem
+	for _, item := range items {
+		if item.ParentId == nil {
+			roots = append(roots, item)
+		} else {
+			children[*item.ParentId] = append(children[*item.ParentId], item)
+		}
+	}
+	if parentId != nil {
+		roots = children[*parentId]
+	}
+
+	var nest func(items []todo.TodoItem) []todo.ItemNode
+	nest = func(items []todo.TodoItem) []todo.ItemNode {
+		nodes := make([]todo.ItemNode, len(items))
+		for i, item := range items {
+			nodes[i] = todo.ItemNode{TodoItem: item, Children: nest(children[item.Id])}
+		}
+		return nodes
+	}
+	return nest(roots)
 }
 
-func (s *TodoItemService) Delete(userId, itemId int) error {
-	return s.repo.Delete(userId, itemId)
+// subtree returns the item itemId and its subtasks at any depth out of the
+// items of its list.
+func subtree(items []todo.TodoItem, itemId int) []todo.TodoItem {
+	var found []todo.TodoItem
+	for _, item := range items {
+		if item.Id == itemId {
+			found = append(found, item)
+		}
+	}
+
+	var walk func(nodes []todo.ItemNode)
+	walk = func(nodes []todo.ItemNode) {
+		for _, node := range nodes {
+			found = append(found, node.TodoItem)
+			walk(node.Children)
+		}
+	}
+	walk(itemTree(items, &itemId))
+	return found
 }
 
-func (s *TodoItemService) Update(userId, itemId int, input todo.UpdateItemInput) error {
-	return s.repo.Update(userId, itemId, input)
+// notifyCurrent notifies about a change with the current state of the item.
+func (s *TodoItemService) notifyCurrent(ctx context.Context, userId, itemId int, action string) {
+	item, err := s.repo.GetById(ctx, userId, itemId)
+	if err != nil {
+		log.Error().Err(err).Int("item_id", itemId).Msg("failed with loading item for notifications")
+		return
+	}
+
+	s.notify(ctx, userId, action, item)
+}
+
+// notify tells the stream subscribers of the list and the webhooks about a
+// change. The change is already committed, so failures are only logged.
+func (s *TodoItemService) notify(ctx context.Context, userId int, action string, item todo.TodoItem) {
+	event := events.Event{Type: events.ItemUpdated, ListId: item.ListId, ItemId: item.Id, Item: &item}
+	switch action {
+	case todo.ActionItemCreated, todo.ActionItemRestored, todo.ActionItemMoved:
+		// restored and moved items were new for the subscribers
+		event.Type = events.ItemCreated
+	case todo.ActionItemDeleted:
+		event.Type = events.ItemDeleted
+		event.Item = nil
+	}
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Error().Err(err).Int("item_id", item.Id).Msg("failed with publishing event")
+	}
+
+	triggerWebhooks(ctx, s.deliveries, todo.WebhookPayload{
+		Event: action, ListId: item.ListId, ItemId: &item.Id, ActorId: userId, Data: item,
+	})
 }
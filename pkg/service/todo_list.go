@@ -1,40 +1,141 @@
 package service
 
 import (
+	"context"
 	todo "github.com/LittleMikle/ToDo_List"
 	"github.com/LittleMikle/ToDo_List/pkg/repository"
+	"github.com/rs/zerolog/log"
 )
 
 type TodoListService struct {
-	repo repository.TodoList
+	repo       repository.TodoList
+	itemRepo   repository.TodoItem
+	deliveries repository.WebhookDelivery
 }
 
-func NewTodoListService(repo repository.TodoList) *TodoListService {
+func NewTodoListService(repo repository.TodoList, itemRepo repository.TodoItem, deliveries repository.WebhookDelivery) *TodoListService {
 	return &TodoListService{
-		repo: repo,
+		repo:       repo,
+		itemRepo:   itemRepo,
+		deliveries: deliveries,
 	}
 }
 
-func (s *TodoListService) Create(userId int, list todo.TodoList) (int, error) {
-	return s.repo.Create(userId, list)
+func (s *TodoListService) Create(ctx context.Context, userId int, list todo.TodoList) (int, error) {
+	id, err := s.repo.Create(ctx, userId, list)
+	if err != nil {
+		return 0, err
+	}
+
+	s.notify(ctx, userId, id, todo.ActionListCreated)
+	return id, nil
 }
 
-func (s *TodoListService) GetAll(userId int) ([]todo.TodoList, error) {
-	return s.repo.GetAll(userId)
+func (s *TodoListService) GetAll(ctx context.Context, userId int, query todo.ListQuery) ([]todo.TodoList, todo.PageInfo, error) {
+	query = query.WithDefaultSort(todo.ListSorts)
+	if err := query.Validate(todo.ListSorts); err != nil {
+		return nil, todo.PageInfo{}, err
+	}
+
+	return s.repo.GetAll(ctx, userId, query)
 }
 
-func (s *TodoListService) GetById(userId, listId int) (todo.TodoList, error) {
-	return s.repo.GetById(userId, listId)
+func (s *TodoListService) GetById(ctx context.Context, userId, listId int) (todo.TodoList, error) {
+	return s.repo.GetById(ctx, userId, listId)
 }
 
-func (s *TodoListService) Delete(userId, listId int) error {
-	return s.repo.Delete(userId, listId)
+func (s *TodoListService) Delete(ctx context.Context, userId, listId int) error {
+	list, err := s.repo.GetById(ctx, userId, listId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.Delete(ctx, userId, listId); err != nil {
+		return err
+	}
+
+	triggerWebhooks(ctx, s.deliveries, todo.WebhookPayload{
+		Event: todo.ActionListDeleted, ListId: listId, ActorId: userId, Data: list,
+	})
+	return nil
 }
 
-func (s *TodoListService) Update(userId, listId int, input todo.UpdateListInput) error {
+func (s *TodoListService) Restore(ctx context.Context, userId, listId int) error {
+	if err := s.repo.Restore(ctx, userId, listId); err != nil {
+		return err
+	}
+
+	s.notify(ctx, userId, listId, todo.ActionListRestored)
+	return nil
+}
+
+func (s *TodoListService) Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error {
 	if err := input.Validate(); err != nil {
 		return err
 	}
 
-	return s.repo.Update(userId, listId, input)
+	if err := s.repo.Update(ctx, userId, listId, input); err != nil {
+		return err
+	}
+
+	s.notify(ctx, userId, listId, todo.ActionListUpdated)
+	return nil
+}
+
+func (s *TodoListService) Export(ctx context.Context, userId, listId int) (todo.ListExport, error) {
+	list, err := s.repo.GetById(ctx, userId, listId)
+	if err != nil {
+		return todo.ListExport{}, err
+	}
+
+	items, err := s.itemRepo.GetTree(ctx, userId, listId)
+	if err != nil {
+		return todo.ListExport{}, err
+	}
+
+	return todo.ListExport{
+		Title:       list.Title,
+		Description: list.Description,
+		Items:       exportItems(itemTree(items, nil)),
+	}, nil
+}
+
+func (s *TodoListService) Import(ctx context.Context, userId int, list todo.ListExport) (int, error) {
+	if err := list.Validate(); err != nil {
+		return 0, err
+	}
+
+	id, err := s.repo.Import(ctx, userId, list)
+	if err != nil {
+		return 0, err
+	}
+
+	s.notify(ctx, userId, id, todo.ActionListCreated)
+	return id, nil
+}
+
+func exportItems(nodes []todo.ItemNode) []todo.ExportItem {
+	items := make([]todo.ExportItem, len(nodes))
+	for n, node := range nodes {
+		items[n] = todo.ExportItem{
+			Title:       node.Title,
+			Description: node.Description,
+			Done:        node.Done,
+			Priority:    node.Priority,
+			DueAt:       node.DueAt,
+			Children:    exportItems(node.Children),
+		}
+	}
+	return items
+}
+
+// notify triggers the webhooks of a change with the current state of the list.
+func (s *TodoListService) notify(ctx context.Context, userId, listId int, action string) {
+	list, err := s.repo.GetById(ctx, userId, listId)
+	if err != nil {
+		log.Error().Err(err).Int("list_id", listId).Msg("failed with loading list for webhooks")
+		return
+	}
+
+	triggerWebhooks(ctx, s.deliveries, todo.WebhookPayload{Event: action, ListId: listId, ActorId: userId, Data: list})
 }
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// hashToken returns the hash a random token is stored as. Tokens carry enough
+// entropy for a plain SHA-256 to be safe to look up by.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
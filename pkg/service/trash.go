@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+)
+
+type TrashService struct {
+	repo repository.Trash
+}
+
+func NewTrashService(repo repository.Trash) *TrashService {
+	return &TrashService{repo: repo}
+}
+
+func (s *TrashService) GetAll(ctx context.Context, userId int) (todo.Trash, error) {
+	return s.repo.GetAll(ctx, userId)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type WebhookService struct {
+	repo       repository.Webhook
+	deliveries repository.WebhookDelivery
+}
+
+func NewWebhookService(repo repository.Webhook, deliveries repository.WebhookDelivery) *WebhookService {
+	return &WebhookService{repo: repo, deliveries: deliveries}
+}
+
+// Create returns the secret of the webhook, which is generated when the
+// input has none. It cannot be read back later.
+func (s *WebhookService) Create(ctx context.Context, userId int, input todo.WebhookInput) (int, string, error) {
+	if err := input.Validate(); err != nil {
+		return 0, "", err
+	}
+
+	if input.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return 0, "", err
+		}
+		input.Secret = secret
+	}
+
+	id, err := s.repo.Create(ctx, userId, input)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, input.Secret, nil
+}
+
+func (s *WebhookService) GetAll(ctx context.Context, userId int) ([]todo.Webhook, error) {
+	return s.repo.GetAll(ctx, userId)
+}
+
+func (s *WebhookService) GetById(ctx context.Context, userId, webhookId int) (todo.Webhook, error) {
+	return s.repo.GetById(ctx, userId, webhookId)
+}
+
+func (s *WebhookService) Update(ctx context.Context, userId, webhookId int, input todo.UpdateWebhookInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, userId, webhookId, input)
+}
+
+func (s *WebhookService) Delete(ctx context.Context, userId, webhookId int) error {
+	return s.repo.Delete(ctx, userId, webhookId)
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, userId, webhookId int, query todo.PageQuery) ([]todo.WebhookDelivery, todo.PageInfo, error) {
+	if err := query.Validate(); err != nil {
+		return nil, todo.PageInfo{}, err
+	}
+
+	_, err := s.repo.GetById(ctx, userId, webhookId)
+	if err != nil {
+		return nil, todo.PageInfo{}, err
+	}
+
+	return s.deliveries.GetAll(ctx, webhookId, query)
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// triggerWebhooks queues the deliveries of a change. The change is already
+// committed, so failures are only logged.
+func triggerWebhooks(ctx context.Context, deliveries repository.WebhookDelivery, payload todo.WebhookPayload) {
+	payload.CreatedAt = time.Now().UTC()
+	if _, err := deliveries.Enqueue(ctx, payload); err != nil {
+		log.Error().Err(err).Int("list_id", payload.ListId).Str("event", payload.Event).
+			Msg("failed with queueing webhook deliveries")
+	}
+}
@@ -0,0 +1,96 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader names the columns of CSV exports. Imports need the title column
+// only, in any position. Level is 1 for top-level items and one more for each
+// level of subtasks, which follow their parent.
+var csvHeader = []string{"title", "description", "done", "priority", "due_at", "level"}
+
+func encodeCSV(w io.Writer, list todo.ListExport) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := walk(list.Items, 1, func(item todo.ExportItem, level int) error {
+		dueAt := ""
+		if item.DueAt != nil {
+			dueAt = item.DueAt.Format(time.RFC3339)
+		}
+		return cw.Write([]string{item.Title, item.Description, strconv.FormatBool(item.Done),
+			strconv.Itoa(item.Priority), dueAt, strconv.Itoa(level)})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func decodeCSV(r io.Reader) (todo.ListExport, error) {
+	var list todo.ListExport
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return list, fmt.Errorf("%w: invalid csv: %v", todo.ErrValidation, err)
+	}
+	if len(records) == 0 {
+		return list, fmt.Errorf("%w: csv has no header", todo.ErrValidation)
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for n, name := range records[0] {
+		columns[name] = n
+	}
+	if _, ok := columns["title"]; !ok {
+		return list, fmt.Errorf("%w: csv has no title column", todo.ErrValidation)
+	}
+	value := func(record []string, name string) string {
+		if n, ok := columns[name]; ok {
+			return record[n]
+		}
+		return ""
+	}
+
+	items := make([]todo.ExportItem, 0, len(records)-1)
+	levels := make([]int, 0, len(records)-1)
+	for n, record := range records[1:] {
+		item := todo.ExportItem{Title: value(record, "title"), Description: value(record, "description")}
+		level := 1
+		if s := value(record, "done"); s != "" {
+			if item.Done, err = strconv.ParseBool(s); err != nil {
+				return list, fmt.Errorf("%w: invalid done in row %d", todo.ErrValidation, n+1)
+			}
+		}
+		if s := value(record, "priority"); s != "" {
+			if item.Priority, err = strconv.Atoi(s); err != nil {
+				return list, fmt.Errorf("%w: invalid priority in row %d", todo.ErrValidation, n+1)
+			}
+		}
+		if s := value(record, "due_at"); s != "" {
+			dueAt, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return list, fmt.Errorf("%w: invalid due_at in row %d", todo.ErrValidation, n+1)
+			}
+			item.DueAt = &dueAt
+		}
+		if s := value(record, "level"); s != "" {
+			if level, err = strconv.Atoi(s); err != nil {
+				return list, fmt.Errorf("%w: invalid level in row %d", todo.ErrValidation, n+1)
+			}
+		}
+		items = append(items, item)
+		levels = append(levels, level)
+	}
+
+	list.Items, err = nest(items, levels)
+	return list, err
+}
@@ -0,0 +1,127 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// markdownItem matches a task list item, whose checkbox is optional on import.
+// Every two spaces of indentation nest it one level deeper.
+var markdownItem = regexp.MustCompile(`^( *)[-*+] (?:\[([ xX])\] )?(.*)$`)
+
+// markdownEscaped matches text lines that are escaped with a backslash on
+// export, as they would be taken for items or start with a backslash.
+var markdownEscaped = regexp.MustCompile(`^ *(?:[-*+] |\\)`)
+
+// encodeMarkdown writes the list as a heading followed by its description and
+// a task list. Descriptions of items are indented below them, their lines
+// that look like items are escaped.
+func encodeMarkdown(w io.Writer, list todo.ListExport) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n", oneLine(list.Title))
+	if list.Description != "" {
+		bw.WriteString("\n")
+		for _, line := range strings.Split(list.Description, "\n") {
+			fmt.Fprintf(bw, "%s\n", escapeMarkdown(line))
+		}
+	}
+	if len(list.Items) > 0 {
+		bw.WriteString("\n")
+	}
+
+	walk(list.Items, 1, func(item todo.ExportItem, level int) error {
+		indent := strings.Repeat("  ", level-1)
+		check := " "
+		if item.Done {
+			check = "x"
+		}
+		fmt.Fprintf(bw, "%s- [%s] %s\n", indent, check, oneLine(item.Title))
+		if item.Description != "" {
+			for _, line := range strings.Split(item.Description, "\n") {
+				if strings.TrimSpace(line) == "" {
+					bw.WriteString("\n")
+					continue
+				}
+				fmt.Fprintf(bw, "%s  %s\n", indent, escapeMarkdown(line))
+			}
+		}
+		return nil
+	})
+	return bw.Flush()
+}
+
+// decodeMarkdown takes the first heading as the title of the list and the
+// text up to the first item as its description. Lines indented below an item
+// describe it, blank lines among them included, other text among the items is
+// skipped.
+func decodeMarkdown(r io.Reader) (todo.ListExport, error) {
+	var list todo.ListExport
+	var description []string
+	var items []todo.ExportItem
+	var levels []int
+	indent := 0
+	blank := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(strings.ReplaceAll(scanner.Text(), "\t", "  "), " ")
+		if match := markdownItem.FindStringSubmatch(line); match != nil {
+			indent = len(match[1])
+			items = append(items, todo.ExportItem{Title: strings.TrimSpace(match[3]), Done: match[2] == "x" || match[2] == "X"})
+			levels = append(levels, indent/2+1)
+			blank = 0
+			continue
+		}
+
+		switch {
+		case len(items) > 0:
+			text := strings.TrimLeft(line, " ")
+			if text == "" {
+				blank++
+				continue
+			}
+			if len(line)-len(text) > indent {
+				item := &items[len(items)-1]
+				if item.Description != "" {
+					item.Description += strings.Repeat("\n", blank+1)
+				}
+				item.Description += unescapeMarkdown(text)
+			}
+			blank = 0
+		case list.Title == "" && strings.HasPrefix(line, "# "):
+			list.Title = strings.TrimSpace(line[2:])
+		default:
+			description = append(description, unescapeMarkdown(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return list, fmt.Errorf("%w: invalid markdown: %v", todo.ErrValidation, err)
+	}
+
+	list.Description = strings.TrimSpace(strings.Join(description, "\n"))
+	var err error
+	list.Items, err = nest(items, levels)
+	return list, err
+}
+
+// escapeMarkdown puts a backslash before the text of a line that would be
+// imported as an item.
+func escapeMarkdown(line string) string {
+	if !markdownEscaped.MatchString(line) {
+		return line
+	}
+	text := strings.TrimLeft(line, " ")
+	return line[:len(line)-len(text)] + `\` + text
+}
+
+func unescapeMarkdown(line string) string {
+	text := strings.TrimLeft(line, " ")
+	if !strings.HasPrefix(text, `\`) {
+		return line
+	}
+	return line[:len(line)-len(text)] + text[1:]
+}
@@ -0,0 +1,149 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// priorityLetters maps priorities to the ones of todo.txt, which range from
+// (A) to (Z). Letters after (C) are imported as low.
+var priorityLetters = map[int]string{
+	todo.PriorityHigh:   "A",
+	todo.PriorityMedium: "B",
+	todo.PriorityLow:    "C",
+}
+
+var (
+	todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// encodeTodoTxt writes a line per item, subtasks included. Done items keep
+// their priority in a pri tag, as the format asks, and due times are only
+// written out when they are not at midnight UTC.
+func encodeTodoTxt(w io.Writer, list todo.ListExport) error {
+	bw := bufio.NewWriter(w)
+	walk(list.Items, 1, func(item todo.ExportItem, level int) error {
+		var fields []string
+		letter := priorityLetters[item.Priority]
+		if item.Done {
+			fields = append(fields, "x")
+		} else if letter != "" {
+			fields = append(fields, "("+letter+")")
+		}
+		fields = append(fields, escapeTodoTxt(item.Title))
+		if item.DueAt != nil {
+			fields = append(fields, "due:"+formatDue(*item.DueAt))
+		}
+		if item.Done && letter != "" {
+			fields = append(fields, "pri:"+letter)
+		}
+		bw.WriteString(strings.Join(fields, " ") + "\n")
+		return nil
+	})
+	return bw.Flush()
+}
+
+func decodeTodoTxt(r io.Reader) (todo.ListExport, error) {
+	var list todo.ListExport
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var item todo.ExportItem
+		if fields[0] == "x" {
+			item.Done = true
+			fields = fields[1:]
+		}
+		// the completion and creation dates of done items come first, the
+		// creation date of others follows their priority
+		fields = skipDates(fields, 2)
+		if len(fields) > 0 && todoTxtPriority.MatchString(fields[0]) {
+			item.Priority = letterPriority(fields[0][1:2])
+			fields = skipDates(fields[1:], 1)
+		}
+
+		title := make([]string, 0, len(fields))
+		for _, field := range fields {
+			switch {
+			case strings.HasPrefix(field, `\`):
+				title = append(title, field[1:])
+			case strings.HasPrefix(field, "due:"):
+				dueAt, err := parseDue(field[len("due:"):])
+				if err != nil {
+					return list, err
+				}
+				item.DueAt = &dueAt
+			case strings.HasPrefix(field, "pri:") && todoTxtPriority.MatchString("("+field[len("pri:"):]+")"):
+				item.Priority = letterPriority(field[len("pri:"):])
+			default:
+				title = append(title, field)
+			}
+		}
+		item.Title = strings.Join(title, " ")
+		list.Items = append(list.Items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return list, fmt.Errorf("%w: invalid todo.txt: %v", todo.ErrValidation, err)
+	}
+	return list, nil
+}
+
+// escapeTodoTxt puts a backslash before the words of a title that would be
+// imported as markers, like a leading "x" marking the item done.
+func escapeTodoTxt(title string) string {
+	words := strings.Fields(title)
+	for n, word := range words {
+		marker := strings.HasPrefix(word, `\`) || strings.HasPrefix(word, "due:") || strings.HasPrefix(word, "pri:")
+		if n == 0 {
+			marker = marker || word == "x" || todoTxtPriority.MatchString(word) || todoTxtDate.MatchString(word)
+		}
+		if marker {
+			words[n] = `\` + word
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func skipDates(fields []string, max int) []string {
+	for n := 0; n < max && len(fields) > 0 && todoTxtDate.MatchString(fields[0]); n++ {
+		fields = fields[1:]
+	}
+	return fields
+}
+
+func letterPriority(letter string) int {
+	for priority, l := range priorityLetters {
+		if l == letter {
+			return priority
+		}
+	}
+	return todo.PriorityLow
+}
+
+func formatDue(t time.Time) string {
+	t = t.UTC()
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+func parseDue(s string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, s)
+	}
+	if err != nil {
+		return t, fmt.Errorf("%w: invalid due date %s", todo.ErrValidation, s)
+	}
+	return t, nil
+}
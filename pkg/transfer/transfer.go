@@ -0,0 +1,103 @@
+// Package transfer encodes lists with their items for export and decodes them
+// for import. JSON keeps everything a todo.ListExport holds. CSV keeps the
+// items but not the list, Markdown drops the priorities and due times, and
+// todo.txt drops the list, the descriptions and the nesting of subtasks.
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"io"
+	"strings"
+)
+
+// ContentTypes of the formats, for the responses of exports.
+var ContentTypes = map[string]string{
+	todo.FormatJSON:    "application/json; charset=utf-8",
+	todo.FormatCSV:     "text/csv; charset=utf-8",
+	todo.FormatMD:      "text/markdown; charset=utf-8",
+	todo.FormatTodoTxt: "text/plain; charset=utf-8",
+}
+
+// Extensions of the files of the formats.
+var Extensions = map[string]string{
+	todo.FormatJSON:    "json",
+	todo.FormatCSV:     "csv",
+	todo.FormatMD:      "md",
+	todo.FormatTodoTxt: "txt",
+}
+
+func Encode(w io.Writer, format string, list todo.ListExport) error {
+	switch format {
+	case todo.FormatJSON:
+		return json.NewEncoder(w).Encode(list)
+	case todo.FormatCSV:
+		return encodeCSV(w, list)
+	case todo.FormatMD:
+		return encodeMarkdown(w, list)
+	case todo.FormatTodoTxt:
+		return encodeTodoTxt(w, list)
+	default:
+		return todo.ValidateFormat(format)
+	}
+}
+
+// Decode reads a list in the format. Malformed input is a todo.ErrValidation.
+func Decode(r io.Reader, format string) (todo.ListExport, error) {
+	switch format {
+	case todo.FormatJSON:
+		var list todo.ListExport
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return list, fmt.Errorf("%w: invalid json: %v", todo.ErrValidation, err)
+		}
+		return list, nil
+	case todo.FormatCSV:
+		return decodeCSV(r)
+	case todo.FormatMD:
+		return decodeMarkdown(r)
+	case todo.FormatTodoTxt:
+		return decodeTodoTxt(r)
+	default:
+		return todo.ListExport{}, todo.ValidateFormat(format)
+	}
+}
+
+// nest turns items in preorder with their levels, starting at 1, into a tree.
+func nest(items []todo.ExportItem, levels []int) ([]todo.ExportItem, error) {
+	tree, n := nestLevel(items, levels, 0, 1)
+	if n < len(items) {
+		return nil, fmt.Errorf("%w: item %q is nested deeper than below its predecessor", todo.ErrValidation, items[n].Title)
+	}
+	return tree, nil
+}
+
+// nestLevel returns the items on the level from the n-th one on, with their
+// subtasks, and the index of the first item it did not take.
+func nestLevel(items []todo.ExportItem, levels []int, n, level int) ([]todo.ExportItem, int) {
+	var nodes []todo.ExportItem
+	for n < len(items) && levels[n] == level {
+		node := items[n]
+		node.Children, n = nestLevel(items, levels, n+1, level+1)
+		nodes = append(nodes, node)
+	}
+	return nodes, n
+}
+
+// walk calls fn for the items and their subtasks in preorder.
+func walk(items []todo.ExportItem, level int, fn func(item todo.ExportItem, level int) error) error {
+	for _, item := range items {
+		if err := fn(item, level); err != nil {
+			return err
+		}
+		if err := walk(item.Children, level+1, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// oneLine joins the lines of s for formats with one line per value.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,219 @@
+package transfer
+
+import (
+	"bytes"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testList() todo.ListExport {
+	dueAt := time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC)
+	return todo.ListExport{
+		Title:       "Release",
+		Description: "Steps of every release",
+		Items: []todo.ExportItem{
+			{Title: "Freeze, then branch", Done: true, Priority: todo.PriorityHigh, DueAt: &dueAt, Children: []todo.ExportItem{
+				{Title: "Tag", Description: "v1.2.3\nsigned", Done: true},
+				{Title: "Notes"},
+			}},
+			{Title: "Announce", Priority: todo.PriorityMedium},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	testTable := []struct {
+		format string
+		want   func(list todo.ListExport) todo.ListExport
+	}{
+		{
+			format: todo.FormatJSON,
+			want:   func(list todo.ListExport) todo.ListExport { return list },
+		},
+		{
+			format: todo.FormatCSV,
+			want: func(list todo.ListExport) todo.ListExport {
+				list.Title, list.Description = "", ""
+				return list
+			},
+		},
+		{
+			format: todo.FormatMD,
+			want: func(list todo.ListExport) todo.ListExport {
+				list.Items[0].Priority, list.Items[0].DueAt = todo.PriorityNone, nil
+				list.Items[1].Priority = todo.PriorityNone
+				return list
+			},
+		},
+		{
+			format: todo.FormatTodoTxt,
+			want: func(list todo.ListExport) todo.ListExport {
+				children := list.Items[0].Children
+				children[0].Description = ""
+				list.Items[0].Children = nil
+				list.Title, list.Description = "", ""
+				list.Items = []todo.ExportItem{list.Items[0], children[0], children[1], list.Items[1]}
+				return list
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.format, func(t *testing.T) {
+			var b bytes.Buffer
+			assert.NoError(t, Encode(&b, testCase.format, testList()))
+
+			got, err := Decode(&b, testCase.format)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want(testList()), got)
+		})
+	}
+}
+
+func TestRoundTrip_Escaping(t *testing.T) {
+	list := todo.ListExport{
+		Title:       "Treasure",
+		Description: "- not an item",
+		Items: []todo.ExportItem{
+			{Title: "Dig", Description: "First paragraph\n\nSecond paragraph\n- not a subtask\n\\ kept"},
+			{Title: "x marks the spot"},
+			{Title: "(A) team due:friday", Done: true},
+		},
+	}
+
+	testTable := []struct {
+		format string
+		want   func(list todo.ListExport) todo.ListExport
+	}{
+		{
+			format: todo.FormatMD,
+			want:   func(list todo.ListExport) todo.ListExport { return list },
+		},
+		{
+			format: todo.FormatTodoTxt,
+			want: func(list todo.ListExport) todo.ListExport {
+				list.Title, list.Description, list.Items[0].Description = "", "", ""
+				return list
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.format, func(t *testing.T) {
+			var b bytes.Buffer
+			assert.NoError(t, Encode(&b, testCase.format, list))
+
+			got, err := Decode(&b, testCase.format)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want(list), got)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	testTable := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: todo.FormatMD,
+			want: "# Release\n\nSteps of every release\n\n- [x] Freeze, then branch\n  - [x] Tag\n    v1.2.3\n    signed\n" +
+				"  - [ ] Notes\n- [ ] Announce\n",
+		},
+		{
+			format: todo.FormatTodoTxt,
+			want:   "x Freeze, then branch due:2023-07-10 pri:A\nx Tag\nNotes\n(B) Announce\n",
+		},
+		{
+			format: todo.FormatCSV,
+			want: "title,description,done,priority,due_at,level\n" +
+				"\"Freeze, then branch\",,true,3,2023-07-10T00:00:00Z,1\nTag,\"v1.2.3\nsigned\",true,0,,2\nNotes,,false,0,,2\n" +
+				"Announce,,false,2,,1\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.format, func(t *testing.T) {
+			var b bytes.Buffer
+			assert.NoError(t, Encode(&b, testCase.format, testList()))
+			assert.Equal(t, testCase.want, b.String())
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	dueAt := time.Date(2023, 7, 10, 9, 30, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		format  string
+		input   string
+		want    todo.ListExport
+		wantErr bool
+	}{
+		{
+			name:   "Markdown Without Checkboxes",
+			format: todo.FormatMD,
+			input:  "# Groceries\n\n* milk\n\t* [X] oat\n    2 litres\n\nSome notes\n- [ ] bread\n",
+			want: todo.ListExport{Title: "Groceries", Items: []todo.ExportItem{
+				{Title: "milk", Children: []todo.ExportItem{{Title: "oat", Done: true, Description: "2 litres"}}},
+				{Title: "bread"},
+			}},
+		},
+		{
+			name:   "Todo.txt With Dates",
+			format: todo.FormatTodoTxt,
+			input:  "x 2023-07-11 2023-07-01 Call Mom +family pri:B\n(A) 2023-07-01 Pay rent due:2023-07-10T09:30:00Z\n\n(D) Read\n",
+			want: todo.ListExport{Items: []todo.ExportItem{
+				{Title: "Call Mom +family", Done: true, Priority: todo.PriorityMedium},
+				{Title: "Pay rent", Priority: todo.PriorityHigh, DueAt: &dueAt},
+				{Title: "Read", Priority: todo.PriorityLow},
+			}},
+		},
+		{
+			name:   "CSV Columns In Any Order",
+			format: todo.FormatCSV,
+			input:  "done,title\ntrue,Sweep\n",
+			want:   todo.ListExport{Items: []todo.ExportItem{{Title: "Sweep", Done: true}}},
+		},
+		{
+			name:    "CSV Without Title",
+			format:  todo.FormatCSV,
+			input:   "name,done\nSweep,true\n",
+			wantErr: true,
+		},
+		{
+			name:    "CSV Level Gap",
+			format:  todo.FormatCSV,
+			input:   "title,level\nSweep,1\nKitchen,3\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Due Date",
+			format:  todo.FormatTodoTxt,
+			input:   "Pay rent due:tomorrow\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			format:  todo.FormatJSON,
+			input:   `{"title":`,
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Decode(strings.NewReader(testCase.input), testCase.format)
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, todo.ErrValidation)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+		})
+	}
+}
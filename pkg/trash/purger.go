@@ -0,0 +1,55 @@
+package trash
+
+import (
+	"context"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type Config struct {
+	Interval time.Duration
+	// Retention is how long deleted lists and items stay restorable.
+	Retention time.Duration
+}
+
+// Purger periodically removes lists and items that have been in the trash
+// for longer than the retention. Running it on several replicas is harmless.
+type Purger struct {
+	repo repository.Trash
+	cfg  Config
+}
+
+func NewPurger(repo repository.Trash, cfg Config) *Purger {
+	return &Purger{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// Run purges the trash every interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	purged, err := p.repo.Purge(ctx, p.cfg.Retention)
+	if err != nil {
+		log.Error().Err(err).Msg("failed with trash purge")
+		return
+	}
+	if purged > 0 {
+		log.Info().Msgf("purged %d rows from trash", purged)
+	}
+}
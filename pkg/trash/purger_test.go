@@ -0,0 +1,31 @@
+package trash
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeRepo struct {
+	retentions []time.Duration
+}
+
+func (r *fakeRepo) GetAll(ctx context.Context, userId int) (todo.Trash, error) {
+	return todo.Trash{}, nil
+}
+
+func (r *fakeRepo) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	r.retentions = append(r.retentions, retention)
+	return 2, nil
+}
+
+func TestPurger_purge(t *testing.T) {
+	repo := &fakeRepo{}
+	p := NewPurger(repo, Config{Interval: time.Hour, Retention: 30 * 24 * time.Hour})
+
+	p.purge(context.Background())
+
+	assert.Equal(t, []time.Duration{30 * 24 * time.Hour}, repo.retentions)
+}
@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/LittleMikle/ToDo_List/pkg/repository"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Headers sent with every delivery. SignatureHeader holds "sha256=" and the
+// hex HMAC-SHA256 of the body keyed with the secret of the webhook.
+const (
+	EventHeader     = "X-Todo-Event"
+	DeliveryHeader  = "X-Todo-Delivery"
+	SignatureHeader = "X-Todo-Signature-256"
+)
+
+type Config struct {
+	Interval    time.Duration
+	Lease       time.Duration
+	Timeout     time.Duration
+	BatchSize   int
+	MaxAttempts int
+	// DisableAfter is the number of failed attempts in a row after which a
+	// webhook is disabled.
+	DisableAfter int
+}
+
+// Dispatcher periodically sends queued deliveries to their webhooks. Like
+// reminder.Scheduler it is safe to run on several replicas at once.
+type Dispatcher struct {
+	repo   repository.WebhookDelivery
+	client *http.Client
+	cfg    Config
+}
+
+func NewDispatcher(repo repository.WebhookDelivery, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: newClient(cfg.Timeout, todo.WebhookAddrAllowed),
+		cfg:    cfg,
+	}
+}
+
+// newClient returns a client that only connects to the addresses allow
+// accepts. They are checked when dialing, after names were resolved, so that
+// a name can't be rebound to a private address after the webhook was saved.
+// Redirects are not followed and proxies not used, as both would bypass the
+// check.
+func newClient(timeout time.Duration, allow func(addr netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !allow(addrPort.Addr()) {
+				return fmt.Errorf("webhook address %s is not allowed", address)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run sends pending deliveries every interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		d.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := d.repo.Claim(ctx, d.cfg.BatchSize, d.cfg.Lease)
+		if err != nil {
+			log.Error().Err(err).Msg("failed with webhook deliveries claim")
+			return
+		}
+
+		for _, delivery := range deliveries {
+			d.deliver(ctx, delivery)
+		}
+
+		if len(deliveries) < d.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery todo.WebhookDelivery) {
+	statusCode, err := d.send(ctx, delivery)
+	if err == nil {
+		if err = d.repo.MarkDelivered(ctx, delivery.Id, statusCode); err != nil {
+			log.Error().Err(err).Msgf("failed to mark webhook delivery %d as delivered", delivery.Id)
+		}
+		return
+	}
+
+	log.Warn().Err(err).Msgf("failed to deliver webhook delivery %d, attempt %d", delivery.Id, delivery.Attempts)
+
+	var retryIn *time.Duration
+	if delivery.Attempts < d.cfg.MaxAttempts {
+		backoff := d.backoff(delivery.Attempts)
+		retryIn = &backoff
+	}
+	err = d.repo.MarkFailed(ctx, delivery.Id, statusCode, err.Error(), retryIn, d.cfg.DisableAfter)
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to mark webhook delivery %d as failed", delivery.Id)
+	}
+}
+
+// send posts the delivery and returns the status code of the response, or 0
+// if there was none.
+func (d *Dispatcher) send(ctx context.Context, delivery todo.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.Itoa(delivery.Id))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed with webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff doubles the retry delay with every attempt, starting at the interval.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 10 {
+		attempts = 10
+	}
+	return d.cfg.Interval << (attempts - 1)
+}
+
+// Sign returns the signature of a body for SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	todo "github.com/LittleMikle/ToDo_List"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+type failure struct {
+	statusCode int
+	retryIn    *time.Duration
+}
+
+type fakeRepo struct {
+	batches   [][]todo.WebhookDelivery
+	delivered map[int]int
+	failed    map[int]failure
+}
+
+func (r *fakeRepo) GetAll(ctx context.Context, webhookId int, query todo.PageQuery) ([]todo.WebhookDelivery, todo.PageInfo, error) {
+	return nil, todo.PageInfo{}, nil
+}
+
+func (r *fakeRepo) Enqueue(ctx context.Context, payload todo.WebhookPayload) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]todo.WebhookDelivery, error) {
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch, nil
+}
+
+func (r *fakeRepo) MarkDelivered(ctx context.Context, id, statusCode int) error {
+	r.delivered[id] = statusCode
+	return nil
+}
+
+func (r *fakeRepo) MarkFailed(ctx context.Context, id, statusCode int, reason string, retryIn *time.Duration, disableAfter int) error {
+	r.failed[id] = failure{statusCode: statusCode, retryIn: retryIn}
+	return nil
+}
+
+func TestDispatcher_tick(t *testing.T) {
+	payload := []byte(`{"event":"item_created"}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign("secret", body) || r.Header.Get(EventHeader) != "item_created" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/ok", http.StatusFound)
+			return
+		}
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &fakeRepo{
+		batches: [][]todo.WebhookDelivery{
+			{
+				{Id: 1, Event: "item_created", Payload: payload, Attempts: 1, URL: server.URL + "/ok", Secret: "secret"},
+				{Id: 2, Event: "item_created", Payload: payload, Attempts: 2, URL: server.URL + "/down", Secret: "secret"},
+			},
+			{
+				{Id: 3, Event: "item_created", Payload: payload, Attempts: 3, URL: server.URL + "/down", Secret: "secret"},
+				{Id: 4, Event: "item_created", Payload: payload, Attempts: 1, URL: server.URL + "/ok", Secret: "wrong"},
+			},
+			{
+				{Id: 5, Event: "item_created", Payload: payload, Attempts: 1, URL: server.URL + "/moved", Secret: "secret"},
+			},
+		},
+		delivered: map[int]int{},
+		failed:    map[int]failure{},
+	}
+
+	d := NewDispatcher(repo, Config{Interval: time.Minute, Timeout: time.Second, BatchSize: 2, MaxAttempts: 3})
+	// the test server listens on loopback
+	d.client = newClient(time.Second, func(addr netip.Addr) bool { return true })
+
+	d.tick(context.Background())
+
+	assert.Equal(t, map[int]int{1: http.StatusNoContent}, repo.delivered)
+	assert.Len(t, repo.failed, 4)
+	assert.Equal(t, http.StatusServiceUnavailable, repo.failed[2].statusCode)
+	assert.Equal(t, 2*time.Minute, *repo.failed[2].retryIn)
+	// the last attempt gives up
+	assert.Nil(t, repo.failed[3].retryIn)
+	assert.Equal(t, http.StatusUnauthorized, repo.failed[4].statusCode)
+	// redirects are not followed
+	assert.Equal(t, http.StatusFound, repo.failed[5].statusCode)
+	assert.Empty(t, repo.batches)
+}
+
+func TestDispatcher_send_PrivateAddress(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(&fakeRepo{}, Config{Interval: time.Minute, Timeout: time.Second})
+
+	statusCode, err := d.send(context.Background(), todo.WebhookDelivery{Id: 1, URL: server.URL, Secret: "secret"})
+	assert.ErrorContains(t, err, "is not allowed")
+	assert.Equal(t, 0, statusCode)
+	assert.Equal(t, 0, requests)
+}
@@ -0,0 +1,167 @@
+package todo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	RepeatDaily     = "daily"
+	RepeatWeekly    = "weekly"
+	RepeatMonthly   = "monthly"
+	RepeatAfterDone = "after_done"
+)
+
+const maxRepeatInterval = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Recurrence is the rule of a series of items, modelled on the RRULE of
+// iCalendar. Weekly items repeat on the Weekdays, given as MO to SU, or on
+// the weekday they are due; monthly items on the MonthDay, moved to the last
+// day of shorter months, which Anchor sets to the day they are due. Interval
+// counts the days, weeks or months between occurrences and defaults to 1.
+// After_done items are due Interval days after the previous one is done.
+type Recurrence struct {
+	Freq     string   `json:"freq" binding:"required"`
+	Interval int      `json:"interval,omitempty"`
+	Weekdays []string `json:"weekdays,omitempty"`
+	MonthDay int      `json:"month_day,omitempty"`
+}
+
+func (r Recurrence) Validate() error {
+	switch r.Freq {
+	case RepeatDaily, RepeatWeekly, RepeatMonthly, RepeatAfterDone:
+	default:
+		return fmt.Errorf("%w: freq must be daily, weekly, monthly or after_done", ErrValidation)
+	}
+	if r.Interval < 0 || r.Interval > maxRepeatInterval {
+		return fmt.Errorf("%w: interval must be between 1 and %d", ErrValidation, maxRepeatInterval)
+	}
+	if len(r.Weekdays) > 0 && r.Freq != RepeatWeekly {
+		return fmt.Errorf("%w: weekdays are only allowed for weekly items", ErrValidation)
+	}
+	seen := make(map[string]bool, len(r.Weekdays))
+	for _, day := range r.Weekdays {
+		if _, ok := weekdays[day]; !ok {
+			return fmt.Errorf("%w: weekday %s must be one of MO, TU, WE, TH, FR, SA, SU", ErrValidation, day)
+		}
+		if seen[day] {
+			return fmt.Errorf("%w: weekday %s is given twice", ErrValidation, day)
+		}
+		seen[day] = true
+	}
+	if r.MonthDay != 0 && r.Freq != RepeatMonthly {
+		return fmt.Errorf("%w: month_day is only allowed for monthly items", ErrValidation)
+	}
+	if r.MonthDay < 0 || r.MonthDay > 31 {
+		return fmt.Errorf("%w: month_day must be between 1 and 31", ErrValidation)
+	}
+	return nil
+}
+
+// Anchor returns the rule with the MonthDay of monthly rules set to the day
+// of the month the item is due, if it has a due time. Without it, the first
+// occurrence moved to the end of a shorter month would move all later ones.
+func (r Recurrence) Anchor(due *time.Time) Recurrence {
+	if r.Freq == RepeatMonthly && r.MonthDay == 0 && due != nil {
+		r.MonthDay = due.Day()
+	}
+	return r
+}
+
+// Next returns when the occurrence that follows the one due at due and done
+// at doneAt is due. Calendar rules go on from the due time, or from doneAt for
+// items without one, and skip the occurrences that are past at doneAt.
+func (r Recurrence) Next(due *time.Time, doneAt time.Time) time.Time {
+	if r.Freq == RepeatAfterDone {
+		return doneAt.AddDate(0, 0, r.interval())
+	}
+
+	base := doneAt
+	if due != nil {
+		base = *due
+	}
+	next := r.after(base)
+	for !next.After(doneAt) {
+		next = r.after(next)
+	}
+	return next
+}
+
+// after returns the first occurrence after the one at t, keeping its time of
+// day.
+func (r Recurrence) after(t time.Time) time.Time {
+	interval := r.interval()
+	switch r.Freq {
+	case RepeatWeekly:
+		days := map[time.Weekday]bool{t.Weekday(): len(r.Weekdays) == 0}
+		for _, day := range r.Weekdays {
+			days[weekdays[day]] = true
+		}
+		week := weekStart(t)
+		for next := t.AddDate(0, 0, 1); ; next = next.AddDate(0, 0, 1) {
+			weeks := int(weekStart(next).Sub(week).Hours()/24+0.5) / 7
+			if weeks%interval == 0 && days[next.Weekday()] {
+				return next
+			}
+		}
+	case RepeatMonthly:
+		day := r.MonthDay
+		if day == 0 {
+			day = t.Day()
+		}
+		for months := 0; ; months += interval {
+			first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+			last := first.AddDate(0, 1, -1).Day()
+			if day < last {
+				last = day
+			}
+			if next := first.AddDate(0, 0, last-1); next.After(t) {
+				return next
+			}
+		}
+	default:
+		return t.AddDate(0, 0, interval)
+	}
+}
+
+func (r Recurrence) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+// weekStart returns the midnight starting the week of t on Monday.
+func weekStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+}
+
+// Value returns a string, as lib/pq would send []byte as bytea.
+func (r Recurrence) Value() (driver.Value, error) {
+	b, err := json.Marshal(r)
+	return string(b), err
+}
+
+func (r *Recurrence) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, r)
+	case string:
+		return json.Unmarshal([]byte(src), r)
+	default:
+		return errors.New("unsupported type of recurrence")
+	}
+}
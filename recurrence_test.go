@@ -0,0 +1,160 @@
+package todo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRecurrence_Next(t *testing.T) {
+	date := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2023, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	due := func(month time.Month, day, hour int) *time.Time {
+		d := date(month, day, hour)
+		return &d
+	}
+
+	testTable := []struct {
+		name   string
+		rule   Recurrence
+		due    *time.Time
+		doneAt time.Time
+		want   time.Time
+	}{
+		{
+			name:   "Daily",
+			rule:   Recurrence{Freq: RepeatDaily, Interval: 2},
+			due:    due(time.July, 10, 9),
+			doneAt: date(time.July, 10, 10),
+			want:   date(time.July, 12, 9),
+		},
+		{
+			name:   "Daily Overdue",
+			rule:   Recurrence{Freq: RepeatDaily},
+			due:    due(time.July, 1, 9),
+			doneAt: date(time.July, 10, 10),
+			want:   date(time.July, 11, 9),
+		},
+		{
+			name:   "Daily Without Due Time",
+			rule:   Recurrence{Freq: RepeatDaily},
+			doneAt: date(time.July, 10, 10),
+			want:   date(time.July, 11, 10),
+		},
+		{
+			name:   "Weekly On Weekdays",
+			rule:   Recurrence{Freq: RepeatWeekly, Weekdays: []string{"TH", "MO"}},
+			due:    due(time.July, 10, 9),
+			doneAt: date(time.July, 10, 10),
+			want:   date(time.July, 13, 9),
+		},
+		{
+			name:   "Weekly Next Week",
+			rule:   Recurrence{Freq: RepeatWeekly, Weekdays: []string{"MO", "TH"}},
+			due:    due(time.July, 13, 9),
+			doneAt: date(time.July, 13, 10),
+			want:   date(time.July, 17, 9),
+		},
+		{
+			name:   "Every Other Week",
+			rule:   Recurrence{Freq: RepeatWeekly, Interval: 2, Weekdays: []string{"MO", "TH"}},
+			due:    due(time.July, 13, 9),
+			doneAt: date(time.July, 13, 10),
+			want:   date(time.July, 24, 9),
+		},
+		{
+			name:   "Weekly On Due Weekday",
+			rule:   Recurrence{Freq: RepeatWeekly},
+			due:    due(time.July, 10, 9),
+			doneAt: date(time.July, 9, 10),
+			want:   date(time.July, 17, 9),
+		},
+		{
+			name:   "Monthly Short Month",
+			rule:   Recurrence{Freq: RepeatMonthly, MonthDay: 31},
+			due:    due(time.January, 31, 9),
+			doneAt: date(time.January, 31, 10),
+			want:   date(time.February, 28, 9),
+		},
+		{
+			name:   "Monthly After Short Month",
+			rule:   Recurrence{Freq: RepeatMonthly, MonthDay: 31},
+			due:    due(time.February, 28, 9),
+			doneAt: date(time.February, 28, 10),
+			want:   date(time.March, 31, 9),
+		},
+		{
+			name:   "Monthly Same Month",
+			rule:   Recurrence{Freq: RepeatMonthly, MonthDay: 15},
+			due:    due(time.July, 10, 9),
+			doneAt: date(time.July, 10, 10),
+			want:   date(time.July, 15, 9),
+		},
+		{
+			name:   "Quarterly On Due Day",
+			rule:   Recurrence{Freq: RepeatMonthly, Interval: 3},
+			due:    due(time.July, 10, 9),
+			doneAt: date(time.July, 10, 10),
+			want:   date(time.October, 10, 9),
+		},
+		{
+			name:   "After Done",
+			rule:   Recurrence{Freq: RepeatAfterDone, Interval: 3},
+			due:    due(time.July, 10, 9),
+			doneAt: date(time.July, 12, 10),
+			want:   date(time.July, 15, 10),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.want, testCase.rule.Next(testCase.due, testCase.doneAt))
+		})
+	}
+}
+
+func TestRecurrence_Anchor(t *testing.T) {
+	due := time.Date(2023, time.January, 31, 9, 0, 0, 0, time.UTC)
+	rule := Recurrence{Freq: RepeatMonthly}.Anchor(&due)
+	assert.Equal(t, Recurrence{Freq: RepeatMonthly, MonthDay: 31}, rule)
+
+	// the series goes back to the 31st after short months
+	var got []string
+	for i := 0; i < 4; i++ {
+		due = rule.Next(&due, due)
+		got = append(got, due.Format("2006-01-02"))
+	}
+	assert.Equal(t, []string{"2023-02-28", "2023-03-31", "2023-04-30", "2023-05-31"}, got)
+
+	assert.Equal(t, Recurrence{Freq: RepeatMonthly, MonthDay: 15}, Recurrence{Freq: RepeatMonthly, MonthDay: 15}.Anchor(&due))
+	assert.Equal(t, Recurrence{Freq: RepeatMonthly}, Recurrence{Freq: RepeatMonthly}.Anchor(nil))
+	assert.Equal(t, Recurrence{Freq: RepeatWeekly}, Recurrence{Freq: RepeatWeekly}.Anchor(&due))
+}
+
+func TestRecurrence_Validate(t *testing.T) {
+	testTable := []struct {
+		name    string
+		rule    Recurrence
+		wantErr bool
+	}{
+		{name: "OK", rule: Recurrence{Freq: RepeatWeekly, Interval: 2, Weekdays: []string{"MO", "FR"}}},
+		{name: "Unknown Freq", rule: Recurrence{Freq: "yearly"}, wantErr: true},
+		{name: "Negative Interval", rule: Recurrence{Freq: RepeatDaily, Interval: -1}, wantErr: true},
+		{name: "Unknown Weekday", rule: Recurrence{Freq: RepeatWeekly, Weekdays: []string{"monday"}}, wantErr: true},
+		{name: "Weekday Twice", rule: Recurrence{Freq: RepeatWeekly, Weekdays: []string{"MO", "MO"}}, wantErr: true},
+		{name: "Weekdays Of Daily", rule: Recurrence{Freq: RepeatDaily, Weekdays: []string{"MO"}}, wantErr: true},
+		{name: "Month Day Out Of Range", rule: Recurrence{Freq: RepeatMonthly, MonthDay: 32}, wantErr: true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.rule.Validate()
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, ErrValidation)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
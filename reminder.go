@@ -0,0 +1,14 @@
+package todo
+
+import "time"
+
+type Reminder struct {
+	Id       int        `json:"-" db:"id"`
+	ItemId   int        `json:"item_id" db:"item_id"`
+	UserId   int        `json:"user_id" db:"user_id"`
+	Username string     `json:"username" db:"username"`
+	Title    string     `json:"title" db:"title"`
+	DueAt    *time.Time `json:"due_at,omitempty" db:"due_at"`
+	RemindAt time.Time  `json:"remind_at" db:"remind_at"`
+	Attempts int        `json:"-" db:"attempts"`
+}
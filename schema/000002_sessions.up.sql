@@ -0,0 +1,9 @@
+CREATE TABLE sessions
+(
+    id                 serial                                      not null unique,
+    user_id            int references users (id) on delete cascade not null,
+    refresh_token_hash varchar(255)                                not null,
+    expires_at         timestamp                                   not null,
+    created_at         timestamp                                   not null default now(),
+    revoked_at         timestamp
+);
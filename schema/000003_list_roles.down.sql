@@ -0,0 +1,2 @@
+ALTER TABLE users_lists
+    DROP COLUMN role;
@@ -0,0 +1,3 @@
+ALTER TABLE users_lists
+    ADD COLUMN role varchar(16) not null default 'owner'
+        CHECK (role IN ('owner', 'editor', 'viewer'));
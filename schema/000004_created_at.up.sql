@@ -0,0 +1,5 @@
+ALTER TABLE todo_lists
+    ADD COLUMN created_at timestamp not null default now();
+
+ALTER TABLE todo_items
+    ADD COLUMN created_at timestamp not null default now();
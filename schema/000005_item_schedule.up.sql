@@ -0,0 +1,6 @@
+ALTER TABLE todo_items
+    ADD COLUMN due_at    timestamp,
+    ADD COLUMN priority  smallint not null default 0 CHECK (priority BETWEEN 0 AND 3),
+    ADD COLUMN remind_at timestamp;
+
+CREATE INDEX todo_items_due_at_idx ON todo_items (due_at) WHERE done = false;
@@ -0,0 +1,15 @@
+CREATE TABLE reminder_deliveries
+(
+    id              serial                                           not null unique,
+    item_id         int references todo_items (id) on delete cascade not null,
+    user_id         int references users (id) on delete cascade      not null,
+    remind_at       timestamp                                        not null,
+    attempts        int                                              not null default 0,
+    last_error      text,
+    next_attempt_at timestamp                                        not null default now(),
+    sent_at         timestamp,
+    created_at      timestamp                                        not null default now(),
+    UNIQUE (item_id, user_id, remind_at)
+);
+
+CREATE INDEX reminder_deliveries_pending_idx ON reminder_deliveries (next_attempt_at) WHERE sent_at IS NULL;
@@ -0,0 +1,19 @@
+ALTER TABLE todo_items DROP COLUMN updated_at;
+ALTER TABLE todo_lists DROP COLUMN updated_at;
+ALTER TABLE users_lists DROP COLUMN created_at, DROP COLUMN updated_at;
+ALTER TABLE users DROP COLUMN created_at, DROP COLUMN updated_at;
+
+DROP INDEX sessions_user_id_idx;
+DROP INDEX lists_items_item_id_idx;
+DROP INDEX users_lists_list_id_idx;
+
+ALTER TABLE lists_items DROP CONSTRAINT lists_items_list_id_item_id_key;
+ALTER TABLE users_lists DROP CONSTRAINT users_lists_user_id_list_id_key;
+
+ALTER TABLE reminder_deliveries DROP CONSTRAINT reminder_deliveries_pkey;
+ALTER TABLE sessions DROP CONSTRAINT sessions_pkey;
+ALTER TABLE lists_items DROP CONSTRAINT lists_items_pkey;
+ALTER TABLE todo_items DROP CONSTRAINT todo_items_pkey;
+ALTER TABLE users_lists DROP CONSTRAINT users_lists_pkey;
+ALTER TABLE todo_lists DROP CONSTRAINT todo_lists_pkey;
+ALTER TABLE users DROP CONSTRAINT users_pkey;
@@ -0,0 +1,37 @@
+DELETE FROM users_lists a USING users_lists b
+WHERE a.user_id = b.user_id AND a.list_id = b.list_id AND a.id > b.id;
+
+DELETE FROM lists_items a USING lists_items b
+WHERE a.list_id = b.list_id AND a.item_id = b.item_id AND a.id > b.id;
+
+ALTER TABLE users ADD PRIMARY KEY (id);
+ALTER TABLE todo_lists ADD PRIMARY KEY (id);
+ALTER TABLE users_lists ADD PRIMARY KEY (id);
+ALTER TABLE todo_items ADD PRIMARY KEY (id);
+ALTER TABLE lists_items ADD PRIMARY KEY (id);
+ALTER TABLE sessions ADD PRIMARY KEY (id);
+ALTER TABLE reminder_deliveries ADD PRIMARY KEY (id);
+
+ALTER TABLE users_lists ADD CONSTRAINT users_lists_user_id_list_id_key UNIQUE (user_id, list_id);
+ALTER TABLE lists_items ADD CONSTRAINT lists_items_list_id_item_id_key UNIQUE (list_id, item_id);
+
+CREATE INDEX users_lists_list_id_idx ON users_lists (list_id);
+CREATE INDEX lists_items_item_id_idx ON lists_items (item_id);
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+
+ALTER TABLE users
+    ADD COLUMN created_at timestamp not null default now(),
+    ADD COLUMN updated_at timestamp not null default now();
+
+ALTER TABLE users_lists
+    ADD COLUMN created_at timestamp not null default now(),
+    ADD COLUMN updated_at timestamp not null default now();
+
+ALTER TABLE todo_lists
+    ADD COLUMN updated_at timestamp not null default now();
+
+ALTER TABLE todo_items
+    ADD COLUMN updated_at timestamp not null default now();
+
+UPDATE todo_lists SET updated_at = created_at;
+UPDATE todo_items SET updated_at = created_at;
@@ -0,0 +1,8 @@
+DELETE FROM todo_items WHERE deleted_at IS NOT NULL;
+DELETE FROM todo_lists WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE todo_items
+    DROP COLUMN deleted_at;
+
+ALTER TABLE todo_lists
+    DROP COLUMN deleted_at;
@@ -0,0 +1,12 @@
+CREATE TABLE activity
+(
+    id         serial primary key,
+    list_id    int references todo_lists (id) on delete cascade not null,
+    item_id    int references todo_items (id) on delete set null,
+    user_id    int references users (id) on delete set null,
+    action     varchar(32) not null,
+    changes    jsonb       not null default '{}',
+    created_at timestamp   not null default now()
+);
+
+CREATE INDEX activity_list_id_idx ON activity (list_id, id);
@@ -0,0 +1,33 @@
+CREATE TABLE webhooks
+(
+    id          serial primary key,
+    user_id     int references users (id) on delete cascade not null,
+    url         varchar(2048)                                not null,
+    secret      varchar(255)                                 not null,
+    events      text[]                                       not null,
+    failures    int                                          not null default 0,
+    disabled_at timestamp,
+    created_at  timestamp                                    not null default now(),
+    updated_at  timestamp                                    not null default now()
+);
+
+CREATE INDEX webhooks_user_id_idx ON webhooks (user_id);
+
+CREATE TABLE webhook_deliveries
+(
+    id              serial primary key,
+    webhook_id      int references webhooks (id) on delete cascade not null,
+    event           varchar(32)                                     not null,
+    payload         jsonb                                           not null,
+    attempts        int                                             not null default 0,
+    status_code     int,
+    last_error      text,
+    next_attempt_at timestamp                                       not null default now(),
+    delivered_at    timestamp,
+    failed_at       timestamp,
+    created_at      timestamp                                       not null default now()
+);
+
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at)
+    WHERE delivered_at IS NULL AND failed_at IS NULL;
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, id);
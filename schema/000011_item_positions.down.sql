@@ -0,0 +1,3 @@
+DROP INDEX lists_items_list_id_position_idx;
+
+ALTER TABLE lists_items DROP COLUMN position;
@@ -0,0 +1,9 @@
+ALTER TABLE lists_items ADD COLUMN position bigint;
+
+UPDATE lists_items li SET position = ordered.rn * 1024
+FROM (SELECT id, row_number() OVER (PARTITION BY list_id ORDER BY item_id) AS rn FROM lists_items) ordered
+WHERE li.id = ordered.id;
+
+ALTER TABLE lists_items ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX lists_items_list_id_position_idx ON lists_items (list_id, position);
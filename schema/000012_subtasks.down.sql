@@ -0,0 +1,5 @@
+DROP INDEX todo_items_parent_id_idx;
+
+ALTER TABLE todo_items
+    DROP COLUMN parent_id,
+    DROP COLUMN auto_complete;
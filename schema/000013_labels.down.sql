@@ -0,0 +1,2 @@
+DROP TABLE items_labels;
+DROP TABLE labels;
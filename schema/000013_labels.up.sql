@@ -0,0 +1,19 @@
+CREATE TABLE labels
+(
+    id         serial primary key,
+    user_id    int references users (id) on delete cascade not null,
+    name       varchar(64)                                  not null,
+    color      varchar(7)                                   not null,
+    created_at timestamp                                    not null default now(),
+    updated_at timestamp                                    not null default now(),
+    unique (user_id, name)
+);
+
+CREATE TABLE items_labels
+(
+    item_id  int references todo_items (id) on delete cascade not null,
+    label_id int references labels (id) on delete cascade     not null,
+    primary key (item_id, label_id)
+);
+
+CREATE INDEX items_labels_label_id_idx ON items_labels (label_id);
@@ -0,0 +1,12 @@
+CREATE TABLE item_series
+(
+    id         serial primary key,
+    rule       jsonb     not null,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now()
+);
+
+ALTER TABLE todo_items
+    ADD COLUMN series_id int references item_series (id) on delete set null;
+
+CREATE INDEX todo_items_series_id_idx ON todo_items (series_id);
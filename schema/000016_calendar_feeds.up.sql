@@ -0,0 +1,6 @@
+CREATE TABLE calendar_feeds
+(
+    user_id    int references users (id) on delete cascade primary key,
+    token_hash varchar(64)                                  not null unique,
+    created_at timestamp                                    not null default now()
+);
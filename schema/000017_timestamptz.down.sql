@@ -0,0 +1,3 @@
+ALTER TABLE todo_items
+    ALTER COLUMN remind_at TYPE timestamp USING remind_at AT TIME ZONE 'UTC',
+    ALTER COLUMN due_at TYPE timestamp USING due_at AT TIME ZONE 'UTC';
@@ -0,0 +1,5 @@
+-- the columns below hold instants given by clients or compared with now(),
+-- stored values were read back as UTC so they are kept as such
+ALTER TABLE todo_items
+    ALTER COLUMN due_at TYPE timestamptz USING due_at AT TIME ZONE 'UTC',
+    ALTER COLUMN remind_at TYPE timestamptz USING remind_at AT TIME ZONE 'UTC';
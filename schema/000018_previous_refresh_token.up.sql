@@ -0,0 +1,2 @@
+ALTER TABLE sessions
+    ADD COLUMN previous_token_hash varchar(255);
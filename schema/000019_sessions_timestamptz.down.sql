@@ -0,0 +1,4 @@
+ALTER TABLE sessions
+    ALTER COLUMN revoked_at TYPE timestamp USING revoked_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN expires_at TYPE timestamp USING expires_at AT TIME ZONE 'UTC';
@@ -0,0 +1,5 @@
+ALTER TABLE todo_items
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE todo_lists
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
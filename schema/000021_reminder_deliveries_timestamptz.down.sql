@@ -0,0 +1,5 @@
+ALTER TABLE reminder_deliveries
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN sent_at TYPE timestamp USING sent_at AT TIME ZONE 'UTC',
+    ALTER COLUMN next_attempt_at TYPE timestamp USING next_attempt_at AT TIME ZONE 'UTC',
+    ALTER COLUMN remind_at TYPE timestamp USING remind_at AT TIME ZONE 'UTC';
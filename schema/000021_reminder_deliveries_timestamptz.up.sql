@@ -0,0 +1,6 @@
+-- stored values were read back as UTC so they are kept as such
+ALTER TABLE reminder_deliveries
+    ALTER COLUMN remind_at TYPE timestamptz USING remind_at AT TIME ZONE 'UTC',
+    ALTER COLUMN next_attempt_at TYPE timestamptz USING next_attempt_at AT TIME ZONE 'UTC',
+    ALTER COLUMN sent_at TYPE timestamptz USING sent_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC';
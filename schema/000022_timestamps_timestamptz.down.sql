@@ -0,0 +1,13 @@
+ALTER TABLE todo_items
+    ALTER COLUMN updated_at TYPE timestamp USING updated_at AT TIME ZONE 'UTC';
+
+ALTER TABLE todo_lists
+    ALTER COLUMN updated_at TYPE timestamp USING updated_at AT TIME ZONE 'UTC';
+
+ALTER TABLE users_lists
+    ALTER COLUMN updated_at TYPE timestamp USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE users
+    ALTER COLUMN updated_at TYPE timestamp USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
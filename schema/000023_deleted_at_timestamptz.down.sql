@@ -0,0 +1,5 @@
+ALTER TABLE todo_items
+    ALTER COLUMN deleted_at TYPE timestamp USING deleted_at AT TIME ZONE 'UTC';
+
+ALTER TABLE todo_lists
+    ALTER COLUMN deleted_at TYPE timestamp USING deleted_at AT TIME ZONE 'UTC';
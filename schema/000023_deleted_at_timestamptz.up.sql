@@ -0,0 +1,6 @@
+-- stored values were read back as UTC so they are kept as such
+ALTER TABLE todo_lists
+    ALTER COLUMN deleted_at TYPE timestamptz USING deleted_at AT TIME ZONE 'UTC';
+
+ALTER TABLE todo_items
+    ALTER COLUMN deleted_at TYPE timestamptz USING deleted_at AT TIME ZONE 'UTC';
@@ -0,0 +1,2 @@
+ALTER TABLE activity
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
@@ -0,0 +1,10 @@
+ALTER TABLE webhook_deliveries
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN failed_at TYPE timestamp USING failed_at AT TIME ZONE 'UTC',
+    ALTER COLUMN delivered_at TYPE timestamp USING delivered_at AT TIME ZONE 'UTC',
+    ALTER COLUMN next_attempt_at TYPE timestamp USING next_attempt_at AT TIME ZONE 'UTC';
+
+ALTER TABLE webhooks
+    ALTER COLUMN updated_at TYPE timestamp USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN disabled_at TYPE timestamp USING disabled_at AT TIME ZONE 'UTC';
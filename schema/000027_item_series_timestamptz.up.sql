@@ -0,0 +1,4 @@
+-- stored values were read back as UTC so they are kept as such
+ALTER TABLE item_series
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE timestamptz USING updated_at AT TIME ZONE 'UTC';
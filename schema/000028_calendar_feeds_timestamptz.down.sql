@@ -0,0 +1,2 @@
+ALTER TABLE calendar_feeds
+    ALTER COLUMN created_at TYPE timestamp USING created_at AT TIME ZONE 'UTC';
@@ -0,0 +1,3 @@
+-- stored values were read back as UTC so they are kept as such
+ALTER TABLE calendar_feeds
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'UTC';
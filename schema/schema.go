@@ -0,0 +1,7 @@
+// Package schema embeds the SQL migrations of the database, see pkg/migrate.
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var Migrations embed.FS
@@ -0,0 +1,47 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	SearchTypeList = "list"
+	SearchTypeItem = "item"
+)
+
+// SearchQuery finds the lists and items whose title or description have
+// words starting with every word of Q.
+type SearchQuery struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit,default=20"`
+}
+
+func (q SearchQuery) Validate() error {
+	if len(q.Terms()) == 0 {
+		return fmt.Errorf("%w: q must contain a word", ErrValidation)
+	}
+	if q.Limit < 1 || q.Limit > MaxPageLimit {
+		return fmt.Errorf("%w: limit must be between 1 and 100", ErrValidation)
+	}
+	return nil
+}
+
+// Terms returns the words of Q, dropping punctuation and other symbols.
+func (q SearchQuery) Terms() []string {
+	return strings.FieldsFunc(q.Q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchResult is a list or an item matching a search. Snippet is HTML in
+// which the matched words are wrapped in <mark> tags.
+type SearchResult struct {
+	Type    string  `json:"type" db:"type"`
+	Id      int     `json:"id" db:"id"`
+	ListId  int     `json:"list_id" db:"list_id"`
+	Title   string  `json:"title" db:"title"`
+	Snippet string  `json:"snippet" db:"snippet"`
+	Rank    float64 `json:"rank" db:"rank"`
+}
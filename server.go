@@ -2,21 +2,29 @@ package todo
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"time"
 )
 
 type Server struct {
 	httpServer *http.Server
+	cancel     context.CancelFunc
 }
 
 func (s *Server) Run(port string, handler http.Handler) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
 	s.httpServer = &http.Server{
 		Addr:           ":" + port,
 		Handler:        handler,
 		MaxHeaderBytes: 1 << 20,
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
 	}
 
 	err := s.httpServer.ListenAndServe()
@@ -27,6 +35,9 @@ func (s *Server) Run(port string, handler http.Handler) error {
 	}
 }
 
+// Shutdown waits for active requests until ctx is done and then cancels the
+// contexts of the remaining ones, aborting their queries.
 func (s *Server) Shutdown(ctx context.Context) error {
+	defer s.cancel()
 	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,20 @@
+package todo
+
+import "time"
+
+type Session struct {
+	Id               int    `db:"id"`
+	UserId           int    `db:"user_id"`
+	RefreshTokenHash string `db:"refresh_token_hash"`
+	// PreviousTokenHash is the hash of the refresh token rotated away last,
+	// presenting it again means the token leaked.
+	PreviousTokenHash *string    `db:"previous_token_hash"`
+	ExpiresAt         time.Time  `db:"expires_at"`
+	CreatedAt         time.Time  `db:"created_at"`
+	RevokedAt         *time.Time `db:"revoked_at"`
+}
+
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
@@ -1,24 +1,86 @@
 package todo
 
-import "errors"
+import (
+	"fmt"
+	"time"
+)
 
 type TodoList struct {
-	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title" binding:"required"`
-	Description string `json:"description" db:"description"`
+	Id          int        `json:"id" db:"id"`
+	Title       string     `json:"title" db:"title" binding:"required"`
+	Description string     `json:"description" db:"description"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
 type UserList struct {
 	Id     int
 	UserId int
 	ListId int
+	Role   string
+}
+
+type Collaborator struct {
+	UserId    int       `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Username  string    `json:"username" db:"username"`
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+const (
+	PriorityNone = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+// MaxItemDepth is how deep subtasks can be nested, top-level items being on
+// the first level.
+const MaxItemDepth = 3
+
+// TodoItem is a subtask of the item ParentId when it is set. An item with
+// AutoComplete is done exactly when all of its subtasks are, as soon as one of
+// them changes. A repeating item belongs to the series SeriesId, whose next
+// occurrence is created once it is done.
 type TodoItem struct {
-	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title" binding:"required"`
-	Description string `json:"description" db:"description"`
-	Done        bool   `json:"done" db:"done"`
+	Id           int         `json:"id" db:"id"`
+	ListId       int         `json:"list_id" db:"list_id"`
+	ParentId     *int        `json:"parent_id,omitempty" db:"parent_id"`
+	Position     int64       `json:"position" db:"position"`
+	Title        string      `json:"title" db:"title" binding:"required"`
+	Description  string      `json:"description" db:"description"`
+	Done         bool        `json:"done" db:"done"`
+	AutoComplete bool        `json:"auto_complete" db:"auto_complete"`
+	DueAt        *time.Time  `json:"due_at,omitempty" db:"due_at"`
+	Priority     int         `json:"priority" db:"priority"`
+	RemindAt     *time.Time  `json:"remind_at,omitempty" db:"remind_at"`
+	SeriesId     *int        `json:"series_id,omitempty" db:"series_id"`
+	Recurrence   *Recurrence `json:"recurrence,omitempty" db:"recurrence"`
+	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
+	DeletedAt    *time.Time  `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// ItemNode is an item with its subtasks.
+type ItemNode struct {
+	TodoItem
+	Children []ItemNode `json:"children"`
+}
+
+// Trash holds the deleted lists and items of a user until they are purged.
+// Items of a deleted list are only restored together with it.
+type Trash struct {
+	Lists []TodoList `json:"lists"`
+	Items []TodoItem `json:"items"`
 }
 
 type ListsItem struct {
@@ -32,15 +94,89 @@ type UpdateListInput struct {
 	Description *string `json:"description"`
 }
 
+// UpdateItemInput changes the set fields of an item. A null due_at or
+// remind_at leaves them unchanged, ClearDueAt and ClearRemindAt remove them.
 type UpdateItemInput struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Done        *bool   `json:"done" db:"done"`
+	Title         *string    `json:"title"`
+	Description   *string    `json:"description"`
+	Done          *bool      `json:"done" db:"done"`
+	AutoComplete  *bool      `json:"auto_complete"`
+	DueAt         *time.Time `json:"due_at"`
+	ClearDueAt    bool       `json:"clear_due_at"`
+	Priority      *int       `json:"priority"`
+	RemindAt      *time.Time `json:"remind_at"`
+	ClearRemindAt bool       `json:"clear_remind_at"`
+}
+
+// ReorderItemsInput moves the items, in this order, right after the item
+// AfterId, or to the top of the list when it is unset. Passing every item of
+// a list reorders it completely.
+type ReorderItemsInput struct {
+	ItemIds []int `json:"item_ids" binding:"required"`
+	AfterId *int  `json:"after_id"`
+}
+
+func (i ReorderItemsInput) Validate() error {
+	if len(i.ItemIds) == 0 {
+		return fmt.Errorf("%w: item_ids must not be empty", ErrValidation)
+	}
+	seen := make(map[int]bool, len(i.ItemIds))
+	for _, id := range i.ItemIds {
+		if seen[id] {
+			return fmt.Errorf("%w: item %d is listed twice", ErrValidation, id)
+		}
+		seen[id] = true
+	}
+	if i.AfterId != nil && seen[*i.AfterId] {
+		return fmt.Errorf("%w: after_id must not be one of the moved items", ErrValidation)
+	}
+	return nil
+}
+
+type ShareListInput struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+}
+
+func (i ShareListInput) Validate() error {
+	if i.Role != RoleEditor && i.Role != RoleViewer {
+		return fmt.Errorf("%w: role must be editor or viewer", ErrValidation)
+	}
+	return nil
 }
 
 func (i UpdateListInput) Validate() error {
 	if i.Title == nil && i.Description == nil {
-		return errors.New("update structure has no values")
+		return fmt.Errorf("%w: update structure has no values", ErrValidation)
+	}
+	return nil
+}
+
+func (i TodoItem) Validate() error {
+	if i.Recurrence != nil {
+		if err := i.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+	return validatePriority(i.Priority)
+}
+
+func (i UpdateItemInput) Validate() error {
+	if i.DueAt != nil && i.ClearDueAt {
+		return fmt.Errorf("%w: due_at can't be set and cleared at once", ErrValidation)
+	}
+	if i.RemindAt != nil && i.ClearRemindAt {
+		return fmt.Errorf("%w: remind_at can't be set and cleared at once", ErrValidation)
+	}
+	if i.Priority != nil {
+		return validatePriority(*i.Priority)
+	}
+	return nil
+}
+
+func validatePriority(priority int) error {
+	if priority < PriorityNone || priority > PriorityHigh {
+		return fmt.Errorf("%w: priority must be between 0 and 3", ErrValidation)
 	}
 	return nil
 }
@@ -0,0 +1,80 @@
+package todo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Formats of exported and imported lists.
+const (
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatMD      = "md"
+	FormatTodoTxt = "todotxt"
+)
+
+// MaxImportItems caps the items of an imported list, subtasks included.
+const MaxImportItems = 1000
+
+// ListExport is a list with its items in their order, as exported and
+// imported.
+type ListExport struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Items       []ExportItem `json:"items"`
+}
+
+type ExportItem struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Done        bool         `json:"done"`
+	Priority    int          `json:"priority,omitempty"`
+	DueAt       *time.Time   `json:"due_at,omitempty"`
+	Children    []ExportItem `json:"children,omitempty"`
+}
+
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatJSON, FormatCSV, FormatMD, FormatTodoTxt:
+		return nil
+	default:
+		return fmt.Errorf("%w: format must be json, csv, md or todotxt", ErrValidation)
+	}
+}
+
+func (l ListExport) Validate() error {
+	if l.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrValidation)
+	}
+	count, err := validateExportItems(l.Items, 1)
+	if err != nil {
+		return err
+	}
+	if count > MaxImportItems {
+		return fmt.Errorf("%w: a list can have at most %d items", ErrValidation, MaxImportItems)
+	}
+	return nil
+}
+
+// validateExportItems checks the items on the given level and below and
+// returns how many there are.
+func validateExportItems(items []ExportItem, depth int) (int, error) {
+	count := len(items)
+	for _, item := range items {
+		if item.Title == "" {
+			return 0, fmt.Errorf("%w: item title is required", ErrValidation)
+		}
+		if err := validatePriority(item.Priority); err != nil {
+			return 0, err
+		}
+		if len(item.Children) > 0 && depth >= MaxItemDepth {
+			return 0, fmt.Errorf("%w: subtasks can be nested at most %d levels deep", ErrValidation, MaxItemDepth)
+		}
+		children, err := validateExportItems(item.Children, depth+1)
+		if err != nil {
+			return 0, err
+		}
+		count += children
+	}
+	return count, nil
+}
@@ -0,0 +1,165 @@
+package todo
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WebhookEvents are the events a webhook can subscribe to. They are named
+// like the activity of a list.
+var WebhookEvents = []string{
+	ActionListCreated, ActionListUpdated, ActionListDeleted, ActionListRestored,
+	ActionItemCreated, ActionItemUpdated, ActionItemDone, ActionItemDeleted, ActionItemRestored,
+	ActionItemMoved, ActionItemsReordered,
+}
+
+// Webhook posts the events of every list its user can access to a URL. It
+// is disabled after too many failed deliveries in a row.
+type Webhook struct {
+	Id         int            `json:"id" db:"id"`
+	URL        string         `json:"url" db:"url"`
+	Events     pq.StringArray `json:"events" db:"events"`
+	Failures   int            `json:"failures" db:"failures"`
+	DisabledAt *time.Time     `json:"disabled_at,omitempty" db:"disabled_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+type WebhookInput struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" binding:"required"`
+}
+
+func (i WebhookInput) Validate() error {
+	if err := validateWebhookURL(i.URL); err != nil {
+		return err
+	}
+	if i.Secret != "" {
+		if err := validateWebhookSecret(i.Secret); err != nil {
+			return err
+		}
+	}
+	return validateWebhookEvents(i.Events)
+}
+
+// UpdateWebhookInput changes a webhook. Setting Active re-enables a disabled
+// webhook or disables it.
+type UpdateWebhookInput struct {
+	URL    *string   `json:"url"`
+	Secret *string   `json:"secret"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
+
+func (i UpdateWebhookInput) Validate() error {
+	if i.URL != nil {
+		if err := validateWebhookURL(*i.URL); err != nil {
+			return err
+		}
+	}
+	if i.Secret != nil {
+		if err := validateWebhookSecret(*i.Secret); err != nil {
+			return err
+		}
+	}
+	if i.Events != nil {
+		return validateWebhookEvents(*i.Events)
+	}
+	return nil
+}
+
+// blockedPrefixes are public-looking ranges webhooks must not reach, besides
+// the private, loopback and link-local ones: "this network" and the shared
+// address space of carrier-grade NAT, where some clouds serve metadata.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// WebhookAddrAllowed reports whether webhooks may be delivered to the address,
+// which must be a public unicast one so that webhooks can't probe the network
+// of the server or its cloud metadata service.
+func WebhookAddrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateWebhookURL rejects hosts that are local by name or address. Names
+// are only resolved when delivering, where the addresses are checked again.
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrValidation)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: url must not point to a local address", ErrValidation)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !WebhookAddrAllowed(addr) {
+		return fmt.Errorf("%w: url must not point to a private, loopback or link-local address", ErrValidation)
+	}
+	return nil
+}
+
+func validateWebhookSecret(secret string) error {
+	if len(secret) < 16 {
+		return fmt.Errorf("%w: secret must be at least 16 characters", ErrValidation)
+	}
+	return nil
+}
+
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return fmt.Errorf("%w: events must not be empty", ErrValidation)
+	}
+	for _, event := range events {
+		if !containsString(WebhookEvents, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrValidation, event)
+		}
+	}
+	return nil
+}
+
+// WebhookPayload is the body posted to webhooks. Data holds the list or item
+// after the change, or before it for deletions.
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	ListId    int         `json:"list_id"`
+	ItemId    *int        `json:"item_id,omitempty"`
+	ActorId   int         `json:"actor_id"`
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// WebhookDelivery is a single event queued for a webhook, kept as the
+// delivery log after it was sent or given up.
+type WebhookDelivery struct {
+	Id            int             `json:"id" db:"id"`
+	WebhookId     int             `json:"webhook_id" db:"webhook_id"`
+	Event         string          `json:"event" db:"event"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	StatusCode    *int            `json:"status_code,omitempty" db:"status_code"`
+	LastError     *string         `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+	FailedAt      *time.Time      `json:"failed_at,omitempty" db:"failed_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	URL           string          `json:"-" db:"url"`
+	Secret        string          `json:"-" db:"secret"`
+}
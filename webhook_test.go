@@ -0,0 +1,50 @@
+package todo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"testing"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	testTable := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://example.com/hooks"},
+		{url: "http://93.184.216.34:8080/hooks"},
+		{url: "https://[2606:2800:220:1:248:1893:25c8:1946]/hooks"},
+		{url: "ftp://example.com", wantErr: true},
+		{url: "/hooks", wantErr: true},
+		{url: "http://localhost:8000", wantErr: true},
+		{url: "http://api.localhost.", wantErr: true},
+		{url: "http://127.0.0.1/hooks", wantErr: true},
+		{url: "http://10.1.2.3/hooks", wantErr: true},
+		{url: "http://192.168.0.1/hooks", wantErr: true},
+		{url: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{url: "http://100.100.100.200/latest/meta-data", wantErr: true},
+		{url: "http://0.0.0.0:8000", wantErr: true},
+		{url: "http://[::1]/hooks", wantErr: true},
+		{url: "http://[::ffff:127.0.0.1]/hooks", wantErr: true},
+		{url: "http://[fd00:ec2::254]/hooks", wantErr: true},
+		{url: "http://[fe80::1]/hooks", wantErr: true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.url, func(t *testing.T) {
+			err := validateWebhookURL(testCase.url)
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, ErrValidation)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookAddrAllowed(t *testing.T) {
+	assert.True(t, WebhookAddrAllowed(netip.MustParseAddr("8.8.8.8")))
+	assert.False(t, WebhookAddrAllowed(netip.MustParseAddr("172.16.0.1")))
+	assert.False(t, WebhookAddrAllowed(netip.MustParseAddr("::ffff:169.254.169.254")))
+	assert.False(t, WebhookAddrAllowed(netip.MustParseAddr("224.0.0.1")))
+}